@@ -0,0 +1,211 @@
+package bitmap
+
+import (
+	"math/bits"
+)
+
+// Allocator 基于分层位图的ID分配器
+// 第0层每一位表示一个ID是否已分配，第k+1层每一位表示第k层对应的字是否已满，
+// 因此查找最小的空闲ID只需从顶层逐层向下，即使有数百万个槽位也只需访问几个字
+type Allocator struct {
+	// ID总数，可分配的ID为[0, size)
+	size uint
+	// levels[0] 已分配的ID；levels[k+1]的第j位表示levels[k][j]已满
+	// 每层超出有效范围的填充位均置为1，从而“已满”可以直接判断为全1
+	levels [][]uint64
+	// 已分配的ID个数
+	used uint
+}
+
+// NewAllocator 创建可分配[0, size)范围内ID的分配器
+func NewAllocator(size uint) *Allocator {
+	var a = &Allocator{size: size}
+	for n := size; ; {
+		var words = uint(wordsNeeded(n))
+		if words == 0 {
+			words = 1
+		}
+		var level = make([]uint64, words)
+		// 填充位置为1
+		if n%wordSize != 0 || n == 0 {
+			level[words-1] = ^uint64(0) << (n % wordSize)
+		}
+		a.levels = append(a.levels, level)
+		if words == 1 {
+			break
+		}
+		n = words
+	}
+	// 填充位使某些字已满时，需要同步到上一层
+	for k := 0; k+1 < len(a.levels); k++ {
+		for j, w := range a.levels[k] {
+			if w == ^uint64(0) {
+				a.levels[k+1][j/wordSize] |= 1 << (uint(j) % wordSize)
+			}
+		}
+	}
+	return a
+}
+
+// Size ID总数
+func (a *Allocator) Size() uint {
+	return a.size
+}
+
+// Used 已分配的ID个数
+func (a *Allocator) Used() uint {
+	return a.used
+}
+
+// Available 空闲的ID个数
+func (a *Allocator) Available() uint {
+	return a.size - a.used
+}
+
+// IsAllocated id是否已分配
+func (a *Allocator) IsAllocated(id uint) bool {
+	return id < a.size && a.levels[0][id/wordSize]&(1<<(id%wordSize)) != 0
+}
+
+// set 将第k层第i位置为1，字由未满变为已满时向上一层传递
+func (a *Allocator) set(k int, i uint) {
+	var w = i / wordSize
+	a.levels[k][w] |= 1 << (i % wordSize)
+	if a.levels[k][w] == ^uint64(0) && k+1 < len(a.levels) {
+		a.set(k+1, w)
+	}
+}
+
+// clear 将第k层第i位置为0，字由已满变为未满时向上一层传递
+func (a *Allocator) clear(k int, i uint) {
+	var (
+		w       = i / wordSize
+		wasFull = a.levels[k][w] == ^uint64(0)
+	)
+	a.levels[k][w] &^= 1 << (i % wordSize)
+	if wasFull && k+1 < len(a.levels) {
+		a.clear(k+1, w)
+	}
+}
+
+// nextZero 第k层中下标不小于from的第一个0位
+func (a *Allocator) nextZero(k int, from uint) (uint, bool) {
+	var level = a.levels[k]
+	for from < uint(len(level))*wordSize {
+		var (
+			w    = from / wordSize
+			word = ^level[w] >> (from % wordSize)
+		)
+		if word != 0 {
+			return from + uint(bits.TrailingZeros64(word)), true
+		}
+		if k+1 == len(a.levels) {
+			// 顶层只有一个字
+			return 0, false
+		}
+		// 借助上一层跳过所有已满的字
+		var next, ok = a.nextZero(k+1, w+1)
+		if !ok {
+			return 0, false
+		}
+		from = next * wordSize
+	}
+	return 0, false
+}
+
+// nextOne 第0层中下标不小于from的第一个1位，不存在时返回size
+func (a *Allocator) nextOne(from uint) uint {
+	var level = a.levels[0]
+	for from < a.size {
+		var (
+			w    = from / wordSize
+			word = level[w] >> (from % wordSize)
+		)
+		if word != 0 {
+			from += uint(bits.TrailingZeros64(word))
+			break
+		}
+		from = (w + 1) * wordSize
+	}
+	if from > a.size {
+		return a.size
+	}
+	return from
+}
+
+// Allocate 分配最小的空闲ID
+func (a *Allocator) Allocate() (uint, error) {
+	var id, ok = a.nextZero(0, 0)
+	if !ok || id >= a.size {
+		return 0, ErrExhausted
+	}
+	a.set(0, id)
+	a.used++
+	return id, nil
+}
+
+// AllocateRange 分配起始位置最小的n个连续空闲ID，返回起始ID
+func (a *Allocator) AllocateRange(n uint) (uint, error) {
+	if n == 0 || n > a.Available() {
+		return 0, ErrExhausted
+	}
+	var from uint
+	for {
+		var start, ok = a.nextZero(0, from)
+		if !ok || start+n > a.size {
+			return 0, ErrExhausted
+		}
+		var end = a.nextOne(start)
+		if end-start >= n {
+			for id := start; id < start+n; id++ {
+				a.set(0, id)
+			}
+			a.used += n
+			return start, nil
+		}
+		from = end
+	}
+}
+
+// AllocateID 分配指定的ID
+func (a *Allocator) AllocateID(id uint) error {
+	if id >= a.size {
+		return ErrOutOfRange
+	}
+	if a.IsAllocated(id) {
+		return ErrAllocated
+	}
+	a.set(0, id)
+	a.used++
+	return nil
+}
+
+// Free 释放ID
+func (a *Allocator) Free(id uint) error {
+	if id >= a.size {
+		return ErrOutOfRange
+	}
+	if !a.IsAllocated(id) {
+		return ErrNotAllocated
+	}
+	a.clear(0, id)
+	a.used--
+	return nil
+}
+
+// FreeRange 释放从start开始的n个连续ID，其中任何一个未分配时不做修改并返回ErrNotAllocated
+func (a *Allocator) FreeRange(start, n uint) error {
+	if start+n > a.size || start+n < start {
+		return ErrOutOfRange
+	}
+	for id := start; id < start+n; id++ {
+		if !a.IsAllocated(id) {
+			return ErrNotAllocated
+		}
+	}
+	for id := start; id < start+n; id++ {
+		a.clear(0, id)
+	}
+	a.used -= n
+	return nil
+}
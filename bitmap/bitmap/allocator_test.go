@@ -0,0 +1,73 @@
+package bitmap
+
+import (
+	"testing"
+)
+
+func TestAllocator_Allocate(t *testing.T) {
+	const size = 64*64*3 + 5
+	var a = NewAllocator(size)
+	for i := uint(0); i < size; i++ {
+		if id, err := a.Allocate(); err != nil || id != i {
+			t.Fatalf("Allocate() = %v, %v, want %v", id, err, i)
+		}
+	}
+	if _, err := a.Allocate(); err != ErrExhausted {
+		t.Fatalf("Allocate() error = %v, want %v", err, ErrExhausted)
+	}
+	// 释放后总是优先分配最小的空闲ID
+	for _, id := range []uint{9000, 77, 4096} {
+		if err := a.Free(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, want := range []uint{77, 4096, 9000} {
+		if id, err := a.Allocate(); err != nil || id != want {
+			t.Fatalf("Allocate() = %v, %v, want %v", id, err, want)
+		}
+	}
+	if err := a.Free(size); err != ErrOutOfRange {
+		t.Fatalf("Free() error = %v, want %v", err, ErrOutOfRange)
+	}
+	if a.Used() != size || a.Available() != 0 {
+		t.Fatalf("Used() = %v, Available() = %v", a.Used(), a.Available())
+	}
+}
+
+func TestAllocator_AllocateRange(t *testing.T) {
+	var a = NewAllocator(1000)
+	for _, id := range []uint{3, 10, 200} {
+		if err := a.AllocateID(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := a.AllocateID(10); err != ErrAllocated {
+		t.Fatalf("AllocateID() error = %v, want %v", err, ErrAllocated)
+	}
+	tests := []struct {
+		n    uint
+		want uint
+	}{
+		{n: 3, want: 0},
+		{n: 6, want: 4},
+		{n: 100, want: 11},
+		{n: 100, want: 201},
+	}
+	for _, tt := range tests {
+		if got, err := a.AllocateRange(tt.n); err != nil || got != tt.want {
+			t.Fatalf("AllocateRange(%d) = %v, %v, want %v", tt.n, got, err, tt.want)
+		}
+	}
+	if _, err := a.AllocateRange(700); err != ErrExhausted {
+		t.Fatalf("AllocateRange() error = %v, want %v", err, ErrExhausted)
+	}
+	if err := a.FreeRange(11, 100); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := a.AllocateRange(90); err != nil || got != 11 {
+		t.Fatalf("AllocateRange(90) = %v, %v, want 11", got, err)
+	}
+	if err := a.FreeRange(100, 20); err != ErrNotAllocated {
+		t.Fatalf("FreeRange() error = %v, want %v", err, ErrNotAllocated)
+	}
+}
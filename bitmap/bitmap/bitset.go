@@ -0,0 +1,342 @@
+package bitmap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/bits"
+	"strings"
+)
+
+const (
+	// wordSize 每个字的位数
+	wordSize = 64
+	// log2WordSize 用于将位索引转换为字索引
+	log2WordSize = 6
+	// readChunkWords 反序列化时每次读取的字数
+	readChunkWords = 512
+)
+
+// MaxSerializedLength 反序列化时允许的最大位集合长度
+const MaxSerializedLength = 1 << 40
+
+// BitSet 可自动扩容的位集合
+type BitSet struct {
+	// 位数组，低位在前
+	set []uint64
+	// 位集合长度
+	length uint
+}
+
+// NewBitSet 创建长度为length的位集合
+func NewBitSet(length uint) *BitSet {
+	return &BitSet{set: make([]uint64, wordsNeeded(length)), length: length}
+}
+
+// wordsNeeded 存放length个位所需的字数
+func wordsNeeded(length uint) int {
+	return int((length + (wordSize - 1)) >> log2WordSize)
+}
+
+// minInt 两个整数中较小的一个
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// wordIndex 位i所在字的索引
+func wordIndex(i uint) int {
+	return int(i >> log2WordSize)
+}
+
+// Len 位集合长度
+func (b *BitSet) Len() uint {
+	return b.length
+}
+
+// extend 确保位集合至少能容纳length个位
+func (b *BitSet) extend(length uint) {
+	if length <= b.length {
+		return
+	}
+	var n = wordsNeeded(length)
+	if n > len(b.set) {
+		if n <= cap(b.set) {
+			b.set = b.set[:n]
+		} else {
+			var set = make([]uint64, n, 2*n)
+			copy(set, b.set)
+			b.set = set
+		}
+	}
+	b.length = length
+}
+
+// trim 清除超出长度部分的位，保证Count等操作结果正确
+func (b *BitSet) trim() {
+	if b.length%wordSize != 0 {
+		b.set[len(b.set)-1] &= (1 << (b.length % wordSize)) - 1
+	}
+}
+
+// Set 将第i位置为1，超出长度时自动扩容
+func (b *BitSet) Set(i uint) *BitSet {
+	b.extend(i + 1)
+	b.set[wordIndex(i)] |= 1 << (i % wordSize)
+	return b
+}
+
+// Clear 将第i位置为0
+func (b *BitSet) Clear(i uint) *BitSet {
+	if i >= b.length {
+		return b
+	}
+	b.set[wordIndex(i)] &^= 1 << (i % wordSize)
+	return b
+}
+
+// Flip 翻转第i位，超出长度时自动扩容
+func (b *BitSet) Flip(i uint) *BitSet {
+	b.extend(i + 1)
+	b.set[wordIndex(i)] ^= 1 << (i % wordSize)
+	return b
+}
+
+// Test 第i位是否为1
+func (b *BitSet) Test(i uint) bool {
+	if i >= b.length {
+		return false
+	}
+	return b.set[wordIndex(i)]&(1<<(i%wordSize)) != 0
+}
+
+// Count 为1的位数
+func (b *BitSet) Count() uint {
+	var cnt int
+	for _, w := range b.set {
+		cnt += bits.OnesCount64(w)
+	}
+	return uint(cnt)
+}
+
+// Any 是否存在为1的位
+func (b *BitSet) Any() bool {
+	for _, w := range b.set {
+		if w != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// NextSet 从第i位（包含）开始查找第一个为1的位
+func (b *BitSet) NextSet(i uint) (uint, bool) {
+	if i >= b.length {
+		return 0, false
+	}
+	var (
+		x = wordIndex(i)
+		w = b.set[x] >> (i % wordSize)
+	)
+	if w != 0 {
+		return i + uint(bits.TrailingZeros64(w)), true
+	}
+	for x++; x < len(b.set); x++ {
+		if b.set[x] != 0 {
+			return uint(x)*wordSize + uint(bits.TrailingZeros64(b.set[x])), true
+		}
+	}
+	return 0, false
+}
+
+// NextClear 从第i位（包含）开始查找第一个为0的位，只在长度范围内查找
+func (b *BitSet) NextClear(i uint) (uint, bool) {
+	if i >= b.length {
+		return 0, false
+	}
+	var (
+		x = wordIndex(i)
+		w = ^b.set[x] >> (i % wordSize)
+	)
+	if w != 0 {
+		if idx := i + uint(bits.TrailingZeros64(w)); idx < b.length {
+			return idx, true
+		}
+		return 0, false
+	}
+	for x++; x < len(b.set); x++ {
+		if b.set[x] != ^uint64(0) {
+			if idx := uint(x)*wordSize + uint(bits.TrailingZeros64(^b.set[x])); idx < b.length {
+				return idx, true
+			}
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// And 原地求交集，结果长度与b相同
+func (b *BitSet) And(other *BitSet) *BitSet {
+	var i int
+	for ; i < len(b.set) && i < len(other.set); i++ {
+		b.set[i] &= other.set[i]
+	}
+	// other较短时，超出部分全部清零
+	for ; i < len(b.set); i++ {
+		b.set[i] = 0
+	}
+	return b
+}
+
+// Or 原地求并集，结果长度为两者中较大者
+func (b *BitSet) Or(other *BitSet) *BitSet {
+	b.extend(other.length)
+	for i := 0; i < len(other.set); i++ {
+		b.set[i] |= other.set[i]
+	}
+	return b
+}
+
+// Xor 原地求对称差，结果长度为两者中较大者
+func (b *BitSet) Xor(other *BitSet) *BitSet {
+	b.extend(other.length)
+	for i := 0; i < len(other.set); i++ {
+		b.set[i] ^= other.set[i]
+	}
+	return b
+}
+
+// AndNot 原地求差集（b中存在而other中不存在），结果长度与b相同
+func (b *BitSet) AndNot(other *BitSet) *BitSet {
+	for i := 0; i < len(b.set) && i < len(other.set); i++ {
+		b.set[i] &^= other.set[i]
+	}
+	return b
+}
+
+// ClearAll 将所有位置为0
+func (b *BitSet) ClearAll() *BitSet {
+	for i := range b.set {
+		b.set[i] = 0
+	}
+	return b
+}
+
+// SetAll 将长度范围内所有位置为1
+func (b *BitSet) SetAll() *BitSet {
+	for i := range b.set {
+		b.set[i] = ^uint64(0)
+	}
+	b.trim()
+	return b
+}
+
+// Clone 深拷贝
+func (b *BitSet) Clone() *BitSet {
+	var c = &BitSet{set: make([]uint64, len(b.set)), length: b.length}
+	copy(c.set, b.set)
+	return c
+}
+
+// Equal 长度与每一位均相同
+func (b *BitSet) Equal(other *BitSet) bool {
+	if b.length != other.length {
+		return false
+	}
+	for i := range b.set {
+		if b.set[i] != other.set[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Words 底层字数组，低位在前，调用方不应修改
+func (b *BitSet) Words() []uint64 {
+	return b.set
+}
+
+// String 以{1,3,5}的形式输出为1的位
+func (b *BitSet) String() string {
+	var (
+		sb    strings.Builder
+		first = true
+	)
+	sb.WriteByte('{')
+	for i, ok := b.NextSet(0); ok; i, ok = b.NextSet(i + 1) {
+		if !first {
+			sb.WriteByte(',')
+		}
+		first = false
+		fmt.Fprintf(&sb, "%d", i)
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+// BinaryLen 序列化后的字节数
+func (b *BitSet) BinaryLen() int {
+	return 8 + 8*len(b.set)
+}
+
+// WriteTo 序列化到w：8字节长度（大端）加上每个字（大端）
+func (b *BitSet) WriteTo(w io.Writer) (int64, error) {
+	var buf = make([]byte, b.BinaryLen())
+	binary.BigEndian.PutUint64(buf, uint64(b.length))
+	for i, word := range b.set {
+		binary.BigEndian.PutUint64(buf[8+8*i:], word)
+	}
+	var n, err = w.Write(buf)
+	return int64(n), err
+}
+
+// ReadFrom 从r中反序列化，覆盖当前内容
+// 长度超过MaxSerializedLength时返回ErrInvalidData，数据按块读取，不会按头部长度预先分配内存
+func (b *BitSet) ReadFrom(r io.Reader) (int64, error) {
+	var head [8]byte
+	if n, err := io.ReadFull(r, head[:]); err != nil {
+		return int64(n), err
+	}
+	var length = binary.BigEndian.Uint64(head[:])
+	if length > MaxSerializedLength || length > uint64(^uint(0))-(wordSize-1) {
+		return 8, ErrInvalidData
+	}
+	var (
+		words = wordsNeeded(uint(length))
+		set   = make([]uint64, 0, minInt(words, readChunkWords))
+		buf   = make([]byte, 8*readChunkWords)
+		read  = int64(8)
+	)
+	for len(set) < words {
+		var chunk = buf[:8*minInt(words-len(set), readChunkWords)]
+		var n, err = io.ReadFull(r, chunk)
+		read += int64(n)
+		if err != nil {
+			return read, err
+		}
+		for i := 0; i < len(chunk); i += 8 {
+			set = append(set, binary.BigEndian.Uint64(chunk[i:]))
+		}
+	}
+	b.set, b.length = set, uint(length)
+	b.trim()
+	return read, nil
+}
+
+// MarshalBinary 实现encoding.BinaryMarshaler
+func (b *BitSet) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := b.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary 实现encoding.BinaryUnmarshaler
+func (b *BitSet) UnmarshalBinary(data []byte) error {
+	var _, err = b.ReadFrom(bytes.NewReader(data))
+	return err
+}
@@ -0,0 +1,111 @@
+package bitmap
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"testing"
+)
+
+func TestBitSet_SetClearFlip(t *testing.T) {
+	var b = NewBitSet(10)
+	b.Set(1).Set(3).Set(130)
+	if b.Len() != 131 {
+		t.Fatalf("Len() = %v, want %v", b.Len(), 131)
+	}
+	if !b.Test(130) || !b.Test(3) || b.Test(2) || b.Test(1000) {
+		t.Fatalf("Test() got wrong result: %v", b)
+	}
+	b.Clear(3).Flip(2).Flip(1)
+	if got := b.String(); got != "{2,130}" {
+		t.Fatalf("String() = %v, want %v", got, "{2,130}")
+	}
+	if b.Count() != 2 {
+		t.Fatalf("Count() = %v, want %v", b.Count(), 2)
+	}
+}
+
+func TestBitSet_Next(t *testing.T) {
+	var b = NewBitSet(200)
+	for _, i := range []uint{0, 63, 64, 150} {
+		b.Set(i)
+	}
+	var got []uint
+	for i, ok := b.NextSet(0); ok; i, ok = b.NextSet(i + 1) {
+		got = append(got, i)
+	}
+	if len(got) != 4 || got[1] != 63 || got[3] != 150 {
+		t.Fatalf("NextSet() = %v", got)
+	}
+	if i, ok := b.NextClear(63); !ok || i != 65 {
+		t.Fatalf("NextClear(63) = %v, %v, want 65, true", i, ok)
+	}
+	b.SetAll()
+	if _, ok := b.NextClear(0); ok {
+		t.Fatal("NextClear() on full bitset should fail")
+	}
+	if b.Count() != 200 {
+		t.Fatalf("Count() = %v, want %v", b.Count(), 200)
+	}
+}
+
+func TestBitSet_SetOperations(t *testing.T) {
+	var newBitSet = func(items ...uint) *BitSet {
+		var b = NewBitSet(0)
+		for _, i := range items {
+			b.Set(i)
+		}
+		return b
+	}
+	tests := []struct {
+		name string
+		op   func(a, b *BitSet) *BitSet
+		want string
+	}{
+		{name: "and", op: (*BitSet).And, want: "{3}"},
+		{name: "or", op: (*BitSet).Or, want: "{1,3,5,100}"},
+		{name: "xor", op: (*BitSet).Xor, want: "{1,5,100}"},
+		{name: "andNot", op: (*BitSet).AndNot, want: "{1}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got = tt.op(newBitSet(1, 3), newBitSet(3, 5, 100))
+			if got.String() != tt.want {
+				t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBitSet_ReadFrom(t *testing.T) {
+	var valid, _ = NewBitSet(130).Set(1).Set(129).MarshalBinary()
+	var header = func(length uint64, body int) []byte {
+		var data = make([]byte, 8+body)
+		binary.BigEndian.PutUint64(data, length)
+		return data
+	}
+	var tests = []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{"valid", valid, nil},
+		{"empty", nil, io.EOF},
+		{"truncated header", valid[:5], io.ErrUnexpectedEOF},
+		{"truncated body", valid[:len(valid)-1], io.ErrUnexpectedEOF},
+		{"overflowing length", header(math.MaxUint64, 0), ErrInvalidData},
+		{"oversized length", header(1<<62, 0), ErrInvalidData},
+		{"length without body", header(MaxSerializedLength, 16), io.ErrUnexpectedEOF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b = NewBitSet(0)
+			if err := b.UnmarshalBinary(tt.data); err != tt.wantErr {
+				t.Fatalf("UnmarshalBinary() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (b.Len() != 130 || !b.Test(129) || b.Count() != 2) {
+				t.Fatalf("UnmarshalBinary() got %v", b)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package bitmap
+
+import (
+	"errors"
+)
+
+var (
+	// ErrExhausted 没有足够的空闲ID
+	ErrExhausted = errors.New("no free id available")
+	// ErrOutOfRange ID超出分配器范围
+	ErrOutOfRange = errors.New("id is out of range")
+	// ErrAllocated ID已被分配
+	ErrAllocated = errors.New("id is already allocated")
+	// ErrNotAllocated ID未被分配
+	ErrNotAllocated = errors.New("id is not allocated")
+	// ErrInvalidData 反序列化的数据不合法
+	ErrInvalidData = errors.New("invalid bitset data")
+)
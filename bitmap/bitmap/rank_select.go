@@ -0,0 +1,198 @@
+package bitmap
+
+import (
+	"math/bits"
+	"sort"
+)
+
+const (
+	// superBlockWords 每个超级块的字数（512位）
+	superBlockWords = 8
+	// selectSampleRate 每隔多少个1（或0）记录一次所在超级块，用于加速Select
+	selectSampleRate = 512
+)
+
+// RankSelect 不可变的简洁位向量
+// 在原始位数组之外只使用少量辅助索引（约为原始大小的40%），支持O(1)的Rank与快速的Select
+type RankSelect struct {
+	words  []uint64
+	length uint
+	// 1的总数
+	ones uint
+	// super[i] 第i个超级块之前1的个数
+	super []uint64
+	// sub[i] 第i个字之前、所在超级块之内1的个数
+	sub []uint16
+	// select1Samples[j] 第j*selectSampleRate个1所在的超级块
+	select1Samples []uint32
+	// select0Samples[j] 第j*selectSampleRate个0所在的超级块
+	select0Samples []uint32
+}
+
+// NewRankSelect 根据位集合b构建简洁位向量，之后对b的修改不会影响结果
+func NewRankSelect(b *BitSet) *RankSelect {
+	var rs = &RankSelect{words: make([]uint64, len(b.set)), length: b.length}
+	copy(rs.words, b.set)
+	rs.build()
+	return rs
+}
+
+// NewRankSelectFromBools 根据布尔数组构建简洁位向量
+func NewRankSelectFromBools(values []bool) *RankSelect {
+	var b = NewBitSet(uint(len(values)))
+	for i, v := range values {
+		if v {
+			b.Set(uint(i))
+		}
+	}
+	return NewRankSelect(b)
+}
+
+// build 构建辅助索引
+func (rs *RankSelect) build() {
+	var (
+		numSuper = (len(rs.words) + superBlockWords - 1) / superBlockWords
+		ones     uint64
+		zeros    uint64
+	)
+	rs.super = make([]uint64, numSuper+1)
+	rs.sub = make([]uint16, len(rs.words))
+	for s := 0; s < numSuper; s++ {
+		rs.super[s] = ones
+		var inner uint16
+		for i := s * superBlockWords; i < (s+1)*superBlockWords && i < len(rs.words); i++ {
+			rs.sub[i] = inner
+			var (
+				cnt   = uint64(bits.OnesCount64(rs.words[i]))
+				valid = uint64(wordSize)
+			)
+			if i == len(rs.words)-1 && rs.length%wordSize != 0 {
+				valid = uint64(rs.length % wordSize)
+			}
+			// 记录跨过采样点的超级块
+			for uint64(len(rs.select1Samples))*selectSampleRate < ones+cnt {
+				rs.select1Samples = append(rs.select1Samples, uint32(s))
+			}
+			for uint64(len(rs.select0Samples))*selectSampleRate < zeros+valid-cnt {
+				rs.select0Samples = append(rs.select0Samples, uint32(s))
+			}
+			ones += cnt
+			zeros += valid - cnt
+			inner += uint16(cnt)
+		}
+	}
+	rs.super[numSuper] = ones
+	rs.ones = uint(ones)
+}
+
+// Len 位向量长度
+func (rs *RankSelect) Len() uint {
+	return rs.length
+}
+
+// Ones 1的个数
+func (rs *RankSelect) Ones() uint {
+	return rs.ones
+}
+
+// Zeros 0的个数
+func (rs *RankSelect) Zeros() uint {
+	return rs.length - rs.ones
+}
+
+// Test 第i位是否为1
+func (rs *RankSelect) Test(i uint) bool {
+	if i >= rs.length {
+		return false
+	}
+	return rs.words[i>>log2WordSize]&(1<<(i%wordSize)) != 0
+}
+
+// Rank1 区间[0, i)内1的个数，i超出长度时按长度计算
+func (rs *RankSelect) Rank1(i uint) uint {
+	if i >= rs.length {
+		return rs.ones
+	}
+	var w = i >> log2WordSize
+	return uint(rs.super[w/superBlockWords]) + uint(rs.sub[w]) +
+		uint(bits.OnesCount64(rs.words[w]&(1<<(i%wordSize)-1)))
+}
+
+// Rank0 区间[0, i)内0的个数，i超出长度时按长度计算
+func (rs *RankSelect) Rank0(i uint) uint {
+	if i >= rs.length {
+		return rs.Zeros()
+	}
+	return i - rs.Rank1(i)
+}
+
+// Select1 第k个1的位置（k从0开始），不存在时返回false
+func (rs *RankSelect) Select1(k uint) (uint, bool) {
+	if k >= rs.ones {
+		return 0, false
+	}
+	var s = rs.findSuper(k, rs.select1Samples, func(s int) uint64 {
+		return rs.super[s]
+	})
+	k -= uint(rs.super[s])
+	for w := s * superBlockWords; ; w++ {
+		var cnt = uint(bits.OnesCount64(rs.words[w]))
+		if k < cnt {
+			return uint(w)*wordSize + selectInWord(rs.words[w], k), true
+		}
+		k -= cnt
+	}
+}
+
+// Select0 第k个0的位置（k从0开始），不存在时返回false
+func (rs *RankSelect) Select0(k uint) (uint, bool) {
+	if k >= rs.Zeros() {
+		return 0, false
+	}
+	var s = rs.findSuper(k, rs.select0Samples, func(s int) uint64 {
+		return uint64(s)*superBlockWords*wordSize - rs.super[s]
+	})
+	k -= uint(s)*superBlockWords*wordSize - uint(rs.super[s])
+	for w := s * superBlockWords; ; w++ {
+		// 最后一个字超出长度的部分恒为0，但k < Zeros()保证不会落在该部分
+		var cnt = uint(bits.OnesCount64(^rs.words[w]))
+		if k < cnt {
+			return uint(w)*wordSize + selectInWord(^rs.words[w], k), true
+		}
+		k -= cnt
+	}
+}
+
+// findSuper 在相邻两个采样点所在的超级块之间二分查找第k个目标位所在的超级块
+// samples为目标位的采样点，before(s)为超级块s之前目标位的个数
+func (rs *RankSelect) findSuper(k uint, samples []uint32, before func(s int) uint64) int {
+	var (
+		j  = int(k / selectSampleRate)
+		lo = int(samples[j])
+		// 第k个目标位不会晚于下一个采样点所在的超级块
+		hi = len(rs.super) - 2
+	)
+	if j+1 < len(samples) {
+		hi = int(samples[j+1])
+	}
+	var n = hi - lo
+	// 第一个before大于k的超级块的前一个
+	return lo + sort.Search(n, func(i int) bool { return before(lo+i+1) > uint64(k) })
+}
+
+// selectInWord 字w中第k个1的位置（k从0开始）
+func selectInWord(w uint64, k uint) uint {
+	// 先按字节跳过，再逐位查找
+	for shift := uint(0); shift < wordSize; shift += 8 {
+		var cnt = uint(bits.OnesCount8(uint8(w >> shift)))
+		if k < cnt {
+			var b = uint8(w >> shift)
+			for ; k > 0; k-- {
+				b &= b - 1
+			}
+			return shift + uint(bits.TrailingZeros8(b))
+		}
+		k -= cnt
+	}
+	return wordSize
+}
@@ -0,0 +1,88 @@
+package bitmap
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestRankSelect(t *testing.T) {
+	var r = rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 63, 64, 65, 511, 512, 513, 10000} {
+		var values = make([]bool, n)
+		for i := range values {
+			values[i] = r.Intn(3) == 0
+		}
+		var (
+			rs        = NewRankSelectFromBools(values)
+			ones, zrs uint
+		)
+		for i, v := range values {
+			if got := rs.Rank1(uint(i)); got != ones {
+				t.Fatalf("n=%d: Rank1(%d) = %v, want %v", n, i, got, ones)
+			}
+			if got := rs.Rank0(uint(i)); got != zrs {
+				t.Fatalf("n=%d: Rank0(%d) = %v, want %v", n, i, got, zrs)
+			}
+			if v {
+				if got, ok := rs.Select1(ones); !ok || got != uint(i) {
+					t.Fatalf("n=%d: Select1(%d) = %v, want %v", n, ones, got, i)
+				}
+				ones++
+			} else {
+				if got, ok := rs.Select0(zrs); !ok || got != uint(i) {
+					t.Fatalf("n=%d: Select0(%d) = %v, want %v", n, zrs, got, i)
+				}
+				zrs++
+			}
+		}
+		if rs.Ones() != ones || rs.Zeros() != zrs || rs.Rank1(uint(n)) != ones {
+			t.Fatalf("n=%d: Ones() = %v, Zeros() = %v, want %v, %v", n, rs.Ones(), rs.Zeros(), ones, zrs)
+		}
+		if _, ok := rs.Select1(ones); ok {
+			t.Fatalf("n=%d: Select1() beyond Ones() should fail", n)
+		}
+		if _, ok := rs.Select0(zrs); ok {
+			t.Fatalf("n=%d: Select0() beyond Zeros() should fail", n)
+		}
+	}
+}
+
+func TestRankSelect_Clustered(t *testing.T) {
+	// 1稀疏与稠密的区域交替出现，使相邻采样点之间跨越的超级块个数差别很大
+	tests := []struct {
+		name string
+		bit  func(i int) bool
+	}{
+		{name: "sparse head", bit: func(i int) bool { return i >= 90000 || i%9973 == 0 }},
+		{name: "dense head", bit: func(i int) bool { return i < 10000 || i%7919 == 0 }},
+		{name: "alternating", bit: func(i int) bool { return (i/20000)%2 == 0 || i%4001 == 0 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var values = make([]bool, 100000)
+			for i := range values {
+				values[i] = tt.bit(i)
+			}
+			var (
+				rs        = NewRankSelectFromBools(values)
+				ones, zrs uint
+			)
+			for i, v := range values {
+				var (
+					got uint
+					ok  bool
+				)
+				if v {
+					got, ok = rs.Select1(ones)
+					ones++
+				} else {
+					got, ok = rs.Select0(zrs)
+					zrs++
+				}
+				if !ok || got != uint(i) {
+					t.Fatalf("select of bit %d = %v, %v", i, got, ok)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package bitmapindex
+
+import (
+	"github.com/1005281342/godatastructures/bitmap/roaring"
+)
+
+// BitmapIndex 位图索引
+// 为每个(列, 值)维护一个Roaring位图，记录取该值的行号，多条件过滤通过位运算完成而无需扫描记录
+type BitmapIndex struct {
+	// columns[列][值] 取该值的行号集合
+	columns map[string]map[interface{}]*roaring.Bitmap
+	// 所有存在的行号，用于求补集
+	rows *roaring.Bitmap
+}
+
+// NewBitmapIndex 创建一个空的位图索引
+func NewBitmapIndex() *BitmapIndex {
+	return &BitmapIndex{columns: make(map[string]map[interface{}]*roaring.Bitmap), rows: roaring.New()}
+}
+
+// Add 记录第row行的column列取值为value，同一列可以有多个取值（如标签）
+// value必须是可比较的类型
+func (idx *BitmapIndex) Add(row uint32, column string, value interface{}) {
+	var values, has = idx.columns[column]
+	if !has {
+		values = make(map[interface{}]*roaring.Bitmap)
+		idx.columns[column] = values
+	}
+	var b = values[value]
+	if b == nil {
+		b = roaring.New()
+		values[value] = b
+	}
+	b.Add(row)
+	idx.rows.Add(row)
+}
+
+// AddRow 记录第row行的多个列取值
+func (idx *BitmapIndex) AddRow(row uint32, attrs map[string]interface{}) {
+	idx.rows.Add(row)
+	for column, value := range attrs {
+		idx.Add(row, column, value)
+	}
+}
+
+// Remove 删除第row行的column列取值value，行本身仍然存在
+func (idx *BitmapIndex) Remove(row uint32, column string, value interface{}) {
+	var values, has = idx.columns[column]
+	if !has {
+		return
+	}
+	if b := values[value]; b != nil {
+		b.Remove(row)
+		if b.IsEmpty() {
+			delete(values, value)
+		}
+	}
+}
+
+// DeleteRow 删除第row行及其所有列取值
+func (idx *BitmapIndex) DeleteRow(row uint32) {
+	if !idx.rows.Contains(row) {
+		return
+	}
+	idx.rows.Remove(row)
+	for _, values := range idx.columns {
+		for value, b := range values {
+			b.Remove(row)
+			if b.IsEmpty() {
+				delete(values, value)
+			}
+		}
+	}
+}
+
+// Rows 所有存在的行号，调用方不应修改
+func (idx *BitmapIndex) Rows() *roaring.Bitmap {
+	return idx.rows
+}
+
+// Len 行数
+func (idx *BitmapIndex) Len() int {
+	return int(idx.rows.Cardinality())
+}
+
+// Values column列出现过的所有取值
+func (idx *BitmapIndex) Values(column string) []interface{} {
+	var values = make([]interface{}, 0, len(idx.columns[column]))
+	for value := range idx.columns[column] {
+		values = append(values, value)
+	}
+	return values
+}
+
+// bitmap (column, value)对应的位图，不存在时返回nil
+func (idx *BitmapIndex) bitmap(column string, value interface{}) *roaring.Bitmap {
+	return idx.columns[column][value]
+}
+
+// Search 返回满足查询条件q的行号集合，结果为新的位图，可自由修改
+func (idx *BitmapIndex) Search(q Query) *roaring.Bitmap {
+	return q.eval(idx)
+}
+
+// Count 满足查询条件q的行数
+func (idx *BitmapIndex) Count(q Query) int {
+	return int(q.eval(idx).Cardinality())
+}
+
+// Each 按行号升序遍历满足查询条件q的行，fn返回false时停止遍历
+func (idx *BitmapIndex) Each(q Query, fn func(row uint32) bool) {
+	q.eval(idx).Each(fn)
+}
@@ -0,0 +1,64 @@
+package bitmapindex
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBitmapIndex_Search(t *testing.T) {
+	type record struct {
+		color string
+		size  int
+		tags  []string
+	}
+	var records = []record{
+		{color: "red", size: 1, tags: []string{"new"}},
+		{color: "blue", size: 2, tags: []string{"sale"}},
+		{color: "red", size: 2, tags: []string{"new", "sale"}},
+		{color: "green", size: 3},
+		{color: "blue", size: 1, tags: []string{"new"}},
+	}
+	var idx = NewBitmapIndex()
+	for i, r := range records {
+		idx.AddRow(uint32(i), map[string]interface{}{"color": r.color, "size": r.size})
+		for _, tag := range r.tags {
+			idx.Add(uint32(i), "tag", tag)
+		}
+	}
+	tests := []struct {
+		name string
+		q    Query
+		want []uint32
+	}{
+		{name: "eq", q: Eq("color", "red"), want: []uint32{0, 2}},
+		{name: "in", q: In("size", 1, 3), want: []uint32{0, 3, 4}},
+		{name: "and", q: And(Eq("color", "red"), Eq("tag", "sale")), want: []uint32{2}},
+		{name: "or", q: Or(Eq("color", "green"), Eq("tag", "sale")), want: []uint32{1, 2, 3}},
+		{name: "not", q: Not(Eq("tag", "new")), want: []uint32{1, 3}},
+		{name: "andNot", q: AndNot(Eq("tag", "new"), Eq("color", "blue")), want: []uint32{0, 2}},
+		{name: "has", q: Not(Has("tag")), want: []uint32{3}},
+		{name: "missing", q: Eq("color", "black"), want: []uint32{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := idx.Search(tt.q).ToArray(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// 查询结果不应影响索引本身
+	idx.Search(Eq("color", "red")).Add(4)
+	if idx.Count(Eq("color", "red")) != 2 {
+		t.Fatal("Search() result shares storage with the index")
+	}
+	idx.DeleteRow(2)
+	var rows []uint32
+	idx.Each(Eq("tag", "sale"), func(row uint32) bool {
+		rows = append(rows, row)
+		return true
+	})
+	if !reflect.DeepEqual(rows, []uint32{1}) {
+		t.Fatalf("Each() after DeleteRow = %v, want %v", rows, []uint32{1})
+	}
+}
@@ -0,0 +1,129 @@
+package bitmapindex
+
+import (
+	"github.com/1005281342/godatastructures/bitmap/roaring"
+)
+
+// Query 查询表达式，由谓词通过And/Or/Not组合而成
+type Query interface {
+	// eval 在索引上求值，返回新的位图
+	eval(idx *BitmapIndex) *roaring.Bitmap
+}
+
+// eqQuery column列取值为value
+type eqQuery struct {
+	column string
+	value  interface{}
+}
+
+func (q eqQuery) eval(idx *BitmapIndex) *roaring.Bitmap {
+	if b := idx.bitmap(q.column, q.value); b != nil {
+		return b.Clone()
+	}
+	return roaring.New()
+}
+
+// Eq column列取值为value的行
+func Eq(column string, value interface{}) Query {
+	return eqQuery{column: column, value: value}
+}
+
+// In column列取值为values之一的行
+func In(column string, values ...interface{}) Query {
+	var qs = make([]Query, len(values))
+	for i, v := range values {
+		qs[i] = Eq(column, v)
+	}
+	return Or(qs...)
+}
+
+// hasQuery column列有任意取值
+type hasQuery struct {
+	column string
+}
+
+func (q hasQuery) eval(idx *BitmapIndex) *roaring.Bitmap {
+	var b = roaring.New()
+	for _, rows := range idx.columns[q.column] {
+		b.Or(rows)
+	}
+	return b
+}
+
+// Has column列有任意取值的行
+func Has(column string) Query {
+	return hasQuery{column: column}
+}
+
+// andQuery 所有子条件同时满足
+type andQuery []Query
+
+func (q andQuery) eval(idx *BitmapIndex) *roaring.Bitmap {
+	if len(q) == 0 {
+		return idx.rows.Clone()
+	}
+	var b = q[0].eval(idx)
+	for _, sub := range q[1:] {
+		// 结果已为空时无需继续求值
+		if b.IsEmpty() {
+			break
+		}
+		b.And(sub.eval(idx))
+	}
+	return b
+}
+
+// And 所有条件同时满足的行，没有条件时返回所有行
+func And(qs ...Query) Query {
+	return andQuery(qs)
+}
+
+// orQuery 满足任一子条件
+type orQuery []Query
+
+func (q orQuery) eval(idx *BitmapIndex) *roaring.Bitmap {
+	var b = roaring.New()
+	for _, sub := range q {
+		b.Or(sub.eval(idx))
+	}
+	return b
+}
+
+// Or 满足任一条件的行，没有条件时返回空集
+func Or(qs ...Query) Query {
+	return orQuery(qs)
+}
+
+// notQuery 不满足子条件
+type notQuery struct {
+	q Query
+}
+
+func (q notQuery) eval(idx *BitmapIndex) *roaring.Bitmap {
+	var b = idx.rows.Clone()
+	b.AndNot(q.q.eval(idx))
+	return b
+}
+
+// Not 不满足条件q的行（在所有存在的行中求补集）
+func Not(q Query) Query {
+	return notQuery{q: q}
+}
+
+// andNotQuery 满足a但不满足b
+type andNotQuery struct {
+	a, b Query
+}
+
+func (q andNotQuery) eval(idx *BitmapIndex) *roaring.Bitmap {
+	var b = q.a.eval(idx)
+	if !b.IsEmpty() {
+		b.AndNot(q.b.eval(idx))
+	}
+	return b
+}
+
+// AndNot 满足条件a但不满足条件b的行，比And(a, Not(b))少一次求补
+func AndNot(a, b Query) Query {
+	return andNotQuery{a: a, b: b}
+}
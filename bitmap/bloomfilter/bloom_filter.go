@@ -0,0 +1,228 @@
+package bloomfilter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/1005281342/godatastructures/bitmap/bitmap"
+)
+
+// MaxHashes 哈希函数个数上限
+const MaxHashes = 64
+
+// BloomFilter 布隆过滤器
+// 判断不存在时一定不存在，判断存在时有一定概率误判
+type BloomFilter struct {
+	// 位数组长度
+	m uint
+	// 哈希函数个数
+	k uint
+	// 位数组
+	b *bitmap.BitSet
+}
+
+// NewBloomFilter 创建位数组长度为m、哈希函数个数为k的布隆过滤器，k不超过MaxHashes与m
+func NewBloomFilter(m, k uint) *BloomFilter {
+	if m < 1 {
+		m = 1
+	}
+	return &BloomFilter{m: m, k: clampHashes(m, k), b: bitmap.NewBitSet(m)}
+}
+
+// clampHashes 将哈希函数个数k限制在[1, min(MaxHashes, m)]内，m不小于1
+func clampHashes(m, k uint) uint {
+	if k < 1 {
+		k = 1
+	}
+	if k > MaxHashes {
+		k = MaxHashes
+	}
+	if k > m {
+		k = m
+	}
+	return k
+}
+
+// NewBloomFilterWithEstimates 根据预计元素个数n和期望误判率fp创建布隆过滤器
+func NewBloomFilterWithEstimates(n uint, fp float64) *BloomFilter {
+	var m, k = EstimateParameters(n, fp)
+	return NewBloomFilter(m, k)
+}
+
+// EstimateParameters 根据预计元素个数n和期望误判率p估算位数组长度m和哈希函数个数k
+// m = -n*ln(p) / (ln2)^2, k = m/n * ln2
+func EstimateParameters(n uint, p float64) (m, k uint) {
+	if n < 1 {
+		n = 1
+	}
+	if p <= 0 || p >= 1 {
+		p = 0.01
+	}
+	m = uint(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	k = uint(math.Ceil(float64(m) / float64(n) * math.Ln2))
+	return m, k
+}
+
+// Cap 位数组长度
+func (f *BloomFilter) Cap() uint {
+	return f.m
+}
+
+// K 哈希函数个数
+func (f *BloomFilter) K() uint {
+	return f.k
+}
+
+// BitSet 底层位数组，调用方不应修改
+func (f *BloomFilter) BitSet() *bitmap.BitSet {
+	return f.b
+}
+
+// Add 添加元素data
+func (f *BloomFilter) Add(data []byte) *BloomFilter {
+	var h = baseHashes(data)
+	for i := uint(0); i < f.k; i++ {
+		f.b.Set(location(h, i, f.m))
+	}
+	return f
+}
+
+// AddString 添加字符串元素
+func (f *BloomFilter) AddString(data string) *BloomFilter {
+	return f.Add([]byte(data))
+}
+
+// Test 元素data是否可能存在
+func (f *BloomFilter) Test(data []byte) bool {
+	var h = baseHashes(data)
+	for i := uint(0); i < f.k; i++ {
+		if !f.b.Test(location(h, i, f.m)) {
+			return false
+		}
+	}
+	return true
+}
+
+// TestString 字符串元素是否可能存在
+func (f *BloomFilter) TestString(data string) bool {
+	return f.Test([]byte(data))
+}
+
+// TestAndAdd 返回元素data添加前是否可能存在，并添加该元素
+func (f *BloomFilter) TestAndAdd(data []byte) bool {
+	var (
+		h       = baseHashes(data)
+		present = true
+	)
+	for i := uint(0); i < f.k; i++ {
+		var l = location(h, i, f.m)
+		if !f.b.Test(l) {
+			present = false
+			f.b.Set(l)
+		}
+	}
+	return present
+}
+
+// Union 将other合并到当前过滤器中，两者的m、k必须一致
+func (f *BloomFilter) Union(other *BloomFilter) error {
+	if f.m != other.m || f.k != other.k {
+		return ErrIncompatible
+	}
+	f.b.Or(other.b)
+	return nil
+}
+
+// FillRatio 位数组中为1的位所占比例
+func (f *BloomFilter) FillRatio() float64 {
+	return float64(f.b.Count()) / float64(f.m)
+}
+
+// ApproximateCount 根据为1的位数估算已添加的元素个数
+// n ≈ -m/k * ln(1 - X/m)，X为为1的位数
+func (f *BloomFilter) ApproximateCount() uint {
+	var x = float64(f.b.Count())
+	if x >= float64(f.m) {
+		// 位数组已满，无法估算，返回理论上限
+		return uint(float64(f.m) / float64(f.k))
+	}
+	return uint(math.Round(-float64(f.m) / float64(f.k) * math.Log(1-x/float64(f.m))))
+}
+
+// EstimatedFalsePositiveRate 按当前填充率估算的误判率
+func (f *BloomFilter) EstimatedFalsePositiveRate() float64 {
+	return math.Pow(f.FillRatio(), float64(f.k))
+}
+
+// ClearAll 清空过滤器
+func (f *BloomFilter) ClearAll() *BloomFilter {
+	f.b.ClearAll()
+	return f
+}
+
+// Copy 深拷贝
+func (f *BloomFilter) Copy() *BloomFilter {
+	return &BloomFilter{m: f.m, k: f.k, b: f.b.Clone()}
+}
+
+// Equal 参数与位数组均相同
+func (f *BloomFilter) Equal(other *BloomFilter) bool {
+	return f.m == other.m && f.k == other.k && f.b.Equal(other.b)
+}
+
+// WriteTo 序列化到w：8字节k（大端）加上位数组
+func (f *BloomFilter) WriteTo(w io.Writer) (int64, error) {
+	var head [8]byte
+	binary.BigEndian.PutUint64(head[:], uint64(f.k))
+	var n, err = w.Write(head[:])
+	if err != nil {
+		return int64(n), err
+	}
+	var nb int64
+	nb, err = f.b.WriteTo(w)
+	return int64(n) + nb, err
+}
+
+// ReadFrom 从r中反序列化，覆盖当前内容
+func (f *BloomFilter) ReadFrom(r io.Reader) (int64, error) {
+	var head [8]byte
+	if n, err := io.ReadFull(r, head[:]); err != nil {
+		return int64(n), err
+	}
+	var k = binary.BigEndian.Uint64(head[:])
+	if k < 1 || k > MaxHashes {
+		return 8, ErrInvalidData
+	}
+	var (
+		b       = bitmap.NewBitSet(0)
+		nb, err = b.ReadFrom(r)
+	)
+	if err == bitmap.ErrInvalidData {
+		return 8 + nb, ErrInvalidData
+	}
+	if err != nil {
+		return 8 + nb, err
+	}
+	if b.Len() < 1 || uint(k) > b.Len() {
+		return 8 + nb, ErrInvalidData
+	}
+	f.m, f.k, f.b = b.Len(), uint(k), b
+	return 8 + nb, nil
+}
+
+// MarshalBinary 实现encoding.BinaryMarshaler
+func (f *BloomFilter) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := f.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary 实现encoding.BinaryUnmarshaler
+func (f *BloomFilter) UnmarshalBinary(data []byte) error {
+	var _, err = f.ReadFrom(bytes.NewReader(data))
+	return err
+}
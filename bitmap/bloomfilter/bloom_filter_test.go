@@ -0,0 +1,98 @@
+package bloomfilter
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestBloomFilter_FalsePositiveRate(t *testing.T) {
+	const n = 10000
+	var f = NewBloomFilterWithEstimates(n, 0.01)
+	for i := 0; i < n; i++ {
+		f.AddString(strconv.Itoa(i))
+	}
+	for i := 0; i < n; i++ {
+		if !f.TestString(strconv.Itoa(i)) {
+			t.Fatalf("TestString(%d) = false, want true", i)
+		}
+	}
+	var fp int
+	for i := n; i < 2*n; i++ {
+		if f.TestString(strconv.Itoa(i)) {
+			fp++
+		}
+	}
+	if rate := float64(fp) / n; rate > 0.02 {
+		t.Errorf("false positive rate = %v, want <= 0.02", rate)
+	}
+	if cnt := f.ApproximateCount(); cnt < n*95/100 || cnt > n*105/100 {
+		t.Errorf("ApproximateCount() = %v, want about %v", cnt, n)
+	}
+}
+
+func TestBloomFilter_Union(t *testing.T) {
+	var (
+		a = NewBloomFilter(1000, 4).AddString("a")
+		b = NewBloomFilter(1000, 4).AddString("b")
+	)
+	if err := a.Union(b); err != nil {
+		t.Fatal(err)
+	}
+	if !a.TestString("a") || !a.TestString("b") {
+		t.Fatal("union lost elements")
+	}
+	if err := a.Union(NewBloomFilter(1000, 3)); err != ErrIncompatible {
+		t.Fatalf("Union() error = %v, want %v", err, ErrIncompatible)
+	}
+}
+
+func TestBloomFilter_Marshal(t *testing.T) {
+	var f = NewBloomFilterWithEstimates(100, 0.001)
+	f.Add([]byte("hello")).Add([]byte("world"))
+	var data, err = f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var g = new(BloomFilter)
+	if err = g.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !g.Equal(f) || !g.Test([]byte("hello")) {
+		t.Fatal("unmarshaled filter differs from the original")
+	}
+	if err = g.UnmarshalBinary(data[:10]); err == nil {
+		t.Fatal("UnmarshalBinary() on truncated data should fail")
+	}
+}
+
+func TestBloomFilter_ReadFromInvalid(t *testing.T) {
+	var encode = func(k, m uint64) []byte {
+		var data = make([]byte, 16+8*((m+63)/64))
+		binary.BigEndian.PutUint64(data, k)
+		binary.BigEndian.PutUint64(data[8:], m)
+		return data
+	}
+	var tests = []struct {
+		name string
+		data []byte
+	}{
+		{"zero hashes", encode(0, 128)},
+		{"too many hashes", encode(MaxHashes+1, 1024)},
+		{"huge hashes", encode(math.MaxUint64, 1024)},
+		{"more hashes than bits", encode(8, 4)},
+		{"empty bitset", encode(1, 0)},
+		{"oversized bitset", encode(4, math.MaxUint64)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := new(BloomFilter).UnmarshalBinary(tt.data); err != ErrInvalidData {
+				t.Fatalf("UnmarshalBinary() error = %v, want %v", err, ErrInvalidData)
+			}
+		})
+	}
+	if f := NewBloomFilter(8, 100); f.K() != 8 {
+		t.Fatalf("NewBloomFilter(8, 100).K() = %v, want 8", f.K())
+	}
+}
@@ -0,0 +1,186 @@
+package bloomfilter
+
+import (
+	"github.com/1005281342/godatastructures/bitmap/bitmap"
+)
+
+const (
+	// CounterWidth4 4位计数器，最大计数15
+	CounterWidth4 uint = 4
+	// CounterWidth8 8位计数器，最大计数255
+	CounterWidth8 uint = 8
+)
+
+// CountingBloomFilter 计数布隆过滤器
+// 使用计数器代替位数组，从而支持删除元素
+// 计数器达到上限后即饱和，饱和的计数器不再增减，以保证删除不会造成误删（假阴性）
+type CountingBloomFilter struct {
+	// 计数器个数
+	m uint
+	// 哈希函数个数
+	k uint
+	// 计数器位宽
+	width uint
+	// 计数器上限
+	max uint8
+	// 计数器数组，width为4时每个字节存放两个计数器
+	counters []byte
+}
+
+// NewCountingBloomFilter 创建计数器个数为m、哈希函数个数为k、计数器位宽为width的计数布隆过滤器，k不超过MaxHashes与m
+func NewCountingBloomFilter(m, k, width uint) (*CountingBloomFilter, error) {
+	if width != CounterWidth4 && width != CounterWidth8 {
+		return nil, ErrCounterWidth
+	}
+	if m < 1 {
+		m = 1
+	}
+	var f = &CountingBloomFilter{m: m, k: clampHashes(m, k), width: width, max: uint8(1<<width - 1)}
+	if width == CounterWidth4 {
+		f.counters = make([]byte, (m+1)/2)
+	} else {
+		f.counters = make([]byte, m)
+	}
+	return f, nil
+}
+
+// NewCountingBloomFilterWithEstimates 根据预计元素个数n和期望误判率fp创建计数布隆过滤器
+func NewCountingBloomFilterWithEstimates(n uint, fp float64, width uint) (*CountingBloomFilter, error) {
+	var m, k = EstimateParameters(n, fp)
+	return NewCountingBloomFilter(m, k, width)
+}
+
+// Cap 计数器个数
+func (f *CountingBloomFilter) Cap() uint {
+	return f.m
+}
+
+// K 哈希函数个数
+func (f *CountingBloomFilter) K() uint {
+	return f.k
+}
+
+// Width 计数器位宽
+func (f *CountingBloomFilter) Width() uint {
+	return f.width
+}
+
+// get 第i个计数器的值
+func (f *CountingBloomFilter) get(i uint) uint8 {
+	if f.width == CounterWidth8 {
+		return f.counters[i]
+	}
+	return f.counters[i/2] >> (4 * (i % 2)) & 0x0f
+}
+
+// set 设置第i个计数器的值
+func (f *CountingBloomFilter) set(i uint, v uint8) {
+	if f.width == CounterWidth8 {
+		f.counters[i] = v
+		return
+	}
+	var shift = 4 * (i % 2)
+	f.counters[i/2] = f.counters[i/2]&^(0x0f<<shift) | v<<shift
+}
+
+// Add 添加元素data
+func (f *CountingBloomFilter) Add(data []byte) *CountingBloomFilter {
+	var h = baseHashes(data)
+	for i := uint(0); i < f.k; i++ {
+		var (
+			l = location(h, i, f.m)
+			c = f.get(l)
+		)
+		// 饱和后不再增加
+		if c < f.max {
+			f.set(l, c+1)
+		}
+	}
+	return f
+}
+
+// AddString 添加字符串元素
+func (f *CountingBloomFilter) AddString(data string) *CountingBloomFilter {
+	return f.Add([]byte(data))
+}
+
+// Test 元素data是否可能存在
+func (f *CountingBloomFilter) Test(data []byte) bool {
+	return f.Count(data) > 0
+}
+
+// TestString 字符串元素是否可能存在
+func (f *CountingBloomFilter) TestString(data string) bool {
+	return f.Test([]byte(data))
+}
+
+// Count 元素data被添加次数的估计值（可能偏大）
+func (f *CountingBloomFilter) Count(data []byte) uint {
+	var (
+		h        = baseHashes(data)
+		minCount = f.max
+	)
+	for i := uint(0); i < f.k; i++ {
+		if c := f.get(location(h, i, f.m)); c < minCount {
+			minCount = c
+		}
+	}
+	return uint(minCount)
+}
+
+// Remove 删除元素data，元素一定不存在时返回false且不做任何修改
+// 对误判为存在的元素执行删除会影响其他元素，调用方应只删除确实添加过的元素
+func (f *CountingBloomFilter) Remove(data []byte) bool {
+	var (
+		h    = baseHashes(data)
+		locs = make([]uint, f.k)
+	)
+	for i := uint(0); i < f.k; i++ {
+		locs[i] = location(h, i, f.m)
+		if f.get(locs[i]) == 0 {
+			return false
+		}
+	}
+	for _, l := range locs {
+		// 饱和的计数器已无法得知真实计数，保持不变
+		if c := f.get(l); c > 0 && c < f.max {
+			f.set(l, c-1)
+		}
+	}
+	return true
+}
+
+// RemoveString 删除字符串元素
+func (f *CountingBloomFilter) RemoveString(data string) bool {
+	return f.Remove([]byte(data))
+}
+
+// Saturated 已饱和的计数器个数
+func (f *CountingBloomFilter) Saturated() uint {
+	var cnt uint
+	for i := uint(0); i < f.m; i++ {
+		if f.get(i) == f.max {
+			cnt++
+		}
+	}
+	return cnt
+}
+
+// ClearAll 清空过滤器
+func (f *CountingBloomFilter) ClearAll() *CountingBloomFilter {
+	for i := range f.counters {
+		f.counters[i] = 0
+	}
+	return f
+}
+
+// ToBloomFilter 转换为参数相同的普通布隆过滤器（计数大于0的位置为1），便于只读分发
+func (f *CountingBloomFilter) ToBloomFilter() *BloomFilter {
+	var b = bitmap.NewBitSet(f.m)
+	for i := uint(0); i < f.m; i++ {
+		if f.get(i) > 0 {
+			b.Set(i)
+		}
+	}
+	return &BloomFilter{m: f.m, k: f.k, b: b}
+}
@@ -0,0 +1,98 @@
+package bloomfilter
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestCountingBloomFilter_Remove(t *testing.T) {
+	for _, width := range []uint{CounterWidth4, CounterWidth8} {
+		var f, err = NewCountingBloomFilterWithEstimates(1000, 0.01, width)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 1000; i++ {
+			f.AddString(strconv.Itoa(i))
+		}
+		for i := 0; i < 500; i++ {
+			if !f.RemoveString(strconv.Itoa(i)) {
+				t.Fatalf("width %d: RemoveString(%d) = false, want true", width, i)
+			}
+		}
+		for i := 500; i < 1000; i++ {
+			if !f.TestString(strconv.Itoa(i)) {
+				t.Fatalf("width %d: TestString(%d) = false after removing other keys", width, i)
+			}
+		}
+		var fp int
+		for i := 0; i < 500; i++ {
+			if f.TestString(strconv.Itoa(i)) {
+				fp++
+			}
+		}
+		if fp > 25 {
+			t.Errorf("width %d: %d removed keys still reported present", width, fp)
+		}
+		var b = f.ToBloomFilter()
+		for i := 500; i < 1000; i++ {
+			if !b.TestString(strconv.Itoa(i)) {
+				t.Fatalf("width %d: ToBloomFilter() lost key %d", width, i)
+			}
+		}
+	}
+}
+
+func TestCountingBloomFilter_Saturation(t *testing.T) {
+	var f, _ = NewCountingBloomFilter(64, 3, CounterWidth4)
+	for i := 0; i < 20; i++ {
+		f.AddString("hot")
+	}
+	if got := f.Count([]byte("hot")); got != 15 {
+		t.Fatalf("Count() = %v, want %v", got, 15)
+	}
+	if f.Saturated() == 0 {
+		t.Fatal("Saturated() = 0, want > 0")
+	}
+	for i := 0; i < 20; i++ {
+		f.RemoveString("hot")
+	}
+	if !f.TestString("hot") {
+		t.Fatal("saturated counters must never be decremented")
+	}
+	if _, err := NewCountingBloomFilter(64, 3, 2); err != ErrCounterWidth {
+		t.Fatalf("NewCountingBloomFilter() error = %v, want %v", err, ErrCounterWidth)
+	}
+}
+
+func TestCountingBloomFilter_ToBloomFilter(t *testing.T) {
+	tests := []struct {
+		name  string
+		m, k  uint
+		wantK uint
+	}{
+		{name: "zero hashes", m: 128, k: 0, wantK: 1},
+		{name: "too many hashes", m: 1024, k: MaxHashes + 10, wantK: MaxHashes},
+		{name: "more hashes than counters", m: 8, k: 20, wantK: 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var f, _ = NewCountingBloomFilter(tt.m, tt.k, CounterWidth8)
+			if f.K() != tt.wantK {
+				t.Fatalf("K() = %v, want %v", f.K(), tt.wantK)
+			}
+			f.AddString("a")
+			// 转换后的布隆过滤器序列化后必须能被读回
+			var data, err = f.ToBloomFilter().MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var g = new(BloomFilter)
+			if err = g.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary() error = %v", err)
+			}
+			if g.K() != tt.wantK || !g.TestString("a") {
+				t.Fatalf("round trip got K() = %v, TestString(a) = %v", g.K(), g.TestString("a"))
+			}
+		})
+	}
+}
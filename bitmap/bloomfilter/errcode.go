@@ -0,0 +1,14 @@
+package bloomfilter
+
+import (
+	"errors"
+)
+
+var (
+	// ErrIncompatible 两个过滤器的参数不一致，无法合并
+	ErrIncompatible = errors.New("bloom filters are incompatible")
+	// ErrInvalidData 反序列化的数据不合法
+	ErrInvalidData = errors.New("invalid bloom filter data")
+	// ErrCounterWidth 计数器位宽只支持4或8
+	ErrCounterWidth = errors.New("counter width must be 4 or 8 bits")
+)
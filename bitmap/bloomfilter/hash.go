@@ -0,0 +1,23 @@
+package bloomfilter
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+
+	"github.com/1005281342/godatastructures/bitmap/internal/hashutil"
+)
+
+// baseHashes 计算data的两个64位基础哈希值
+// 之后通过双重哈希 h1 + i*h2 得到k个位置（Kirsch-Mitzenmacher优化），避免计算k次哈希
+func baseHashes(data []byte) [2]uint64 {
+	var h = fnv.New128a()
+	_, _ = h.Write(data)
+	var sum = h.Sum(nil)
+	// h2为偶数时在m为2的幂的情况下会退化，强制为奇数
+	return [2]uint64{hashutil.Mix64(binary.BigEndian.Uint64(sum[:8])), hashutil.Mix64(binary.BigEndian.Uint64(sum[8:])) | 1}
+}
+
+// location 第i个哈希函数对应的位置
+func location(h [2]uint64, i uint, m uint) uint {
+	return uint((h[0] + uint64(i)*h[1]) % uint64(m))
+}
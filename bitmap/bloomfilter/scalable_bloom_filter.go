@@ -0,0 +1,237 @@
+package bloomfilter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+const (
+	// DefaultGrowth 每个新阶段的容量是上一阶段的倍数
+	DefaultGrowth = 2
+	// DefaultTightening 每个新阶段的误判率是上一阶段的倍数
+	DefaultTightening = 0.85
+)
+
+// stage 可扩展布隆过滤器的一个阶段
+type stage struct {
+	filter *BloomFilter
+	// 该阶段的设计容量
+	capacity uint
+	// 已添加到该阶段的元素个数
+	count uint
+}
+
+// ScalableBloomFilter 可扩展布隆过滤器
+// 当前阶段装满后追加一个容量更大、误判率更低的阶段，误判率按几何级数收紧，
+// 因此无论添加多少元素，整体误判率都不超过 p0/(1-r) = fp
+type ScalableBloomFilter struct {
+	// 整体误判率上限
+	fp float64
+	// 容量增长倍数
+	growth uint
+	// 误判率收紧比例
+	tightening float64
+	// 第一个阶段的容量
+	initialCapacity uint
+	stages          []*stage
+}
+
+// NewScalableBloomFilter 创建初始容量为n、整体误判率上限为fp的可扩展布隆过滤器
+func NewScalableBloomFilter(n uint, fp float64) *ScalableBloomFilter {
+	return NewScalableBloomFilterWithRatios(n, fp, DefaultGrowth, DefaultTightening)
+}
+
+// NewScalableBloomFilterWithRatios 指定容量增长倍数growth与误判率收紧比例tightening创建可扩展布隆过滤器
+func NewScalableBloomFilterWithRatios(n uint, fp float64, growth uint, tightening float64) *ScalableBloomFilter {
+	if n < 1 {
+		n = 1
+	}
+	if fp <= 0 || fp >= 1 {
+		fp = 0.01
+	}
+	if growth < 1 {
+		growth = DefaultGrowth
+	}
+	if tightening <= 0 || tightening >= 1 {
+		tightening = DefaultTightening
+	}
+	var f = &ScalableBloomFilter{fp: fp, growth: growth, tightening: tightening, initialCapacity: n}
+	f.addStage()
+	return f
+}
+
+// addStage 追加一个阶段，第i个阶段容量为 n*growth^i、误判率为 fp*(1-r)*r^i
+func (f *ScalableBloomFilter) addStage() {
+	var (
+		i        = float64(len(f.stages))
+		capacity = uint(float64(f.initialCapacity) * math.Pow(float64(f.growth), i))
+		p        = f.fp * (1 - f.tightening) * math.Pow(f.tightening, i)
+	)
+	f.stages = append(f.stages, &stage{filter: NewBloomFilterWithEstimates(capacity, p), capacity: capacity})
+}
+
+// Stages 当前阶段个数
+func (f *ScalableBloomFilter) Stages() int {
+	return len(f.stages)
+}
+
+// Cap 所有阶段的位数组长度之和
+func (f *ScalableBloomFilter) Cap() uint {
+	var m uint
+	for _, s := range f.stages {
+		m += s.filter.Cap()
+	}
+	return m
+}
+
+// Count 已添加的元素个数（可能存在的元素不会重复计数）
+func (f *ScalableBloomFilter) Count() uint {
+	var n uint
+	for _, s := range f.stages {
+		n += s.count
+	}
+	return n
+}
+
+// Add 添加元素data
+func (f *ScalableBloomFilter) Add(data []byte) *ScalableBloomFilter {
+	f.TestAndAdd(data)
+	return f
+}
+
+// AddString 添加字符串元素
+func (f *ScalableBloomFilter) AddString(data string) *ScalableBloomFilter {
+	return f.Add([]byte(data))
+}
+
+// Test 元素data是否可能存在
+func (f *ScalableBloomFilter) Test(data []byte) bool {
+	// 新阶段元素更多，倒序查找更快命中
+	for i := len(f.stages) - 1; i >= 0; i-- {
+		if f.stages[i].filter.Test(data) {
+			return true
+		}
+	}
+	return false
+}
+
+// TestString 字符串元素是否可能存在
+func (f *ScalableBloomFilter) TestString(data string) bool {
+	return f.Test([]byte(data))
+}
+
+// TestAndAdd 返回元素data添加前是否可能存在，不存在时添加该元素
+func (f *ScalableBloomFilter) TestAndAdd(data []byte) bool {
+	if f.Test(data) {
+		return true
+	}
+	var last = f.stages[len(f.stages)-1]
+	if last.count >= last.capacity {
+		f.addStage()
+		last = f.stages[len(f.stages)-1]
+	}
+	last.filter.Add(data)
+	last.count++
+	return false
+}
+
+// EstimatedFalsePositiveRate 按各阶段当前填充率估算的整体误判率
+func (f *ScalableBloomFilter) EstimatedFalsePositiveRate() float64 {
+	// 1 - ∏(1 - p_i)
+	var notFp = 1.0
+	for _, s := range f.stages {
+		notFp *= 1 - s.filter.EstimatedFalsePositiveRate()
+	}
+	return 1 - notFp
+}
+
+// ClearAll 清空过滤器，只保留第一个阶段
+func (f *ScalableBloomFilter) ClearAll() *ScalableBloomFilter {
+	f.stages = f.stages[:0]
+	f.addStage()
+	return f
+}
+
+// scalableHeader 序列化头部
+type scalableHeader struct {
+	Fp              float64
+	Tightening      float64
+	Growth          uint64
+	InitialCapacity uint64
+	Stages          uint64
+}
+
+// WriteTo 序列化到w：头部，之后每个阶段依次为容量、元素个数与布隆过滤器，均为大端
+func (f *ScalableBloomFilter) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.BigEndian, scalableHeader{
+		Fp:              f.fp,
+		Tightening:      f.tightening,
+		Growth:          uint64(f.growth),
+		InitialCapacity: uint64(f.initialCapacity),
+		Stages:          uint64(len(f.stages)),
+	})
+	for _, s := range f.stages {
+		_ = binary.Write(&buf, binary.BigEndian, [2]uint64{uint64(s.capacity), uint64(s.count)})
+		if _, err := s.filter.WriteTo(&buf); err != nil {
+			return 0, err
+		}
+	}
+	var n, err = w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// ReadFrom 从r中反序列化，覆盖当前内容
+func (f *ScalableBloomFilter) ReadFrom(r io.Reader) (int64, error) {
+	var h scalableHeader
+	if err := binary.Read(r, binary.BigEndian, &h); err != nil {
+		return 0, err
+	}
+	var n = int64(binary.Size(h))
+	// 写成取反的形式，使NaN同样被拒绝
+	if h.Stages < 1 || h.Growth < 1 || h.InitialCapacity < 1 ||
+		!(h.Fp > 0 && h.Fp < 1) || !(h.Tightening > 0 && h.Tightening < 1) {
+		return n, ErrInvalidData
+	}
+	var stages []*stage
+	for i := uint64(0); i < h.Stages; i++ {
+		var meta [2]uint64
+		if err := binary.Read(r, binary.BigEndian, &meta); err != nil {
+			return n, err
+		}
+		n += 16
+		var (
+			s       = &stage{filter: new(BloomFilter), capacity: uint(meta[0]), count: uint(meta[1])}
+			nb, err = s.filter.ReadFrom(r)
+		)
+		n += nb
+		if err != nil {
+			return n, err
+		}
+		// 阶段的元素个数不超过容量，容量不超过位数组长度
+		if s.capacity < 1 || s.count > s.capacity || s.capacity > s.filter.Cap() {
+			return n, ErrInvalidData
+		}
+		stages = append(stages, s)
+	}
+	f.fp, f.tightening, f.growth, f.initialCapacity = h.Fp, h.Tightening, uint(h.Growth), uint(h.InitialCapacity)
+	f.stages = stages
+	return n, nil
+}
+
+// MarshalBinary 实现encoding.BinaryMarshaler
+func (f *ScalableBloomFilter) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := f.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary 实现encoding.BinaryUnmarshaler
+func (f *ScalableBloomFilter) UnmarshalBinary(data []byte) error {
+	var _, err = f.ReadFrom(bytes.NewReader(data))
+	return err
+}
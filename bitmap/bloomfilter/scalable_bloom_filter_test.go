@@ -0,0 +1,78 @@
+package bloomfilter
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestScalableBloomFilter(t *testing.T) {
+	const n = 20000
+	var f = NewScalableBloomFilter(100, 0.01)
+	for i := 0; i < n; i++ {
+		f.AddString(strconv.Itoa(i))
+	}
+	if f.Stages() < 5 {
+		t.Fatalf("Stages() = %v, want the filter to grow", f.Stages())
+	}
+	for i := 0; i < n; i++ {
+		if !f.TestString(strconv.Itoa(i)) {
+			t.Fatalf("TestString(%d) = false, want true", i)
+		}
+	}
+	var fp int
+	for i := n; i < 2*n; i++ {
+		if f.TestString(strconv.Itoa(i)) {
+			fp++
+		}
+	}
+	if rate := float64(fp) / n; rate > 0.01 {
+		t.Errorf("false positive rate = %v, want <= 0.01", rate)
+	}
+
+	var data, err = f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var g = new(ScalableBloomFilter)
+	if err = g.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if g.Stages() != f.Stages() || g.Count() != f.Count() || !g.TestString("42") {
+		t.Fatal("unmarshaled filter differs from the original")
+	}
+	// 反序列化后可以继续添加
+	g.AddString("new")
+	if !g.TestString("new") {
+		t.Fatal("TestString() = false after Add on unmarshaled filter")
+	}
+}
+
+func TestScalableBloomFilter_ReadFromInvalid(t *testing.T) {
+	var valid, _ = NewScalableBloomFilter(100, 0.01).AddString("a").MarshalBinary()
+	// 偏移：0 Fp, 8 Tightening, 16 Growth, 24 InitialCapacity, 32 Stages，40起为第一个阶段的容量与元素个数
+	var corrupt = func(off int, v uint64) []byte {
+		var data = append([]byte(nil), valid...)
+		binary.BigEndian.PutUint64(data[off:], v)
+		return data
+	}
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "NaN fp", data: corrupt(0, math.Float64bits(math.NaN()))},
+		{name: "NaN tightening", data: corrupt(8, math.Float64bits(math.NaN()))},
+		{name: "fp out of range", data: corrupt(0, math.Float64bits(1.5))},
+		{name: "zero capacity", data: corrupt(40, 0)},
+		{name: "capacity exceeds filter", data: corrupt(40, 1<<40)},
+		{name: "count exceeds capacity", data: corrupt(48, 101)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := new(ScalableBloomFilter).UnmarshalBinary(tt.data); err != ErrInvalidData {
+				t.Fatalf("UnmarshalBinary() error = %v, want %v", err, ErrInvalidData)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package countminsketch
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+
+	"github.com/1005281342/godatastructures/bitmap/internal/hashutil"
+)
+
+// CountMinSketch Count-Min频率估计
+// depth行、每行width个计数器，每个元素在每行命中一个计数器，估计值取各行最小值，只会高估不会低估
+// 误差不超过 epsilon*Total 的概率至少为 1-delta，其中 width=ceil(e/epsilon)、depth=ceil(ln(1/delta))
+type CountMinSketch struct {
+	width uint
+	depth uint
+	// counts[i*width+j] 第i行第j个计数器
+	counts []uint64
+	// 所有元素计数之和
+	total uint64
+	// 是否使用保守更新
+	conservative bool
+}
+
+// New 创建宽度为width、深度为depth的Count-Min sketch
+func New(width, depth uint) *CountMinSketch {
+	if width < 1 {
+		width = 1
+	}
+	if depth < 1 {
+		depth = 1
+	}
+	return &CountMinSketch{width: width, depth: depth, counts: make([]uint64, width*depth)}
+}
+
+// NewWithEstimates 根据相对误差epsilon与失败概率delta创建Count-Min sketch
+func NewWithEstimates(epsilon, delta float64) *CountMinSketch {
+	if epsilon <= 0 || epsilon >= 1 {
+		epsilon = 0.001
+	}
+	if delta <= 0 || delta >= 1 {
+		delta = 0.01
+	}
+	return New(uint(math.Ceil(math.E/epsilon)), uint(math.Ceil(math.Log(1/delta))))
+}
+
+// WithConservativeUpdate 使用保守更新：只增加等于当前估计值的计数器，能明显降低高估，但之后的合并结果不再精确等价
+func (s *CountMinSketch) WithConservativeUpdate() {
+	s.conservative = true
+}
+
+// Width 每行计数器个数
+func (s *CountMinSketch) Width() uint {
+	return s.width
+}
+
+// Depth 行数
+func (s *CountMinSketch) Depth() uint {
+	return s.depth
+}
+
+// Total 所有元素计数之和
+func (s *CountMinSketch) Total() uint64 {
+	return s.total
+}
+
+// locations 元素data在每一行命中的计数器下标，采用双重哈希 h1 + i*h2
+func (s *CountMinSketch) locations(data []byte) []uint {
+	var h = fnv.New128a()
+	_, _ = h.Write(data)
+	var (
+		sum  = h.Sum(nil)
+		h1   = hashutil.Mix64(binary.BigEndian.Uint64(sum[:8]))
+		h2   = hashutil.Mix64(binary.BigEndian.Uint64(sum[8:])) | 1
+		locs = make([]uint, s.depth)
+	)
+	for i := uint(0); i < s.depth; i++ {
+		locs[i] = i*s.width + uint((h1+uint64(i)*h2)%uint64(s.width))
+	}
+	return locs
+}
+
+// Add 元素data的计数增加count
+func (s *CountMinSketch) Add(data []byte, count uint64) {
+	var locs = s.locations(data)
+	s.total += count
+	if !s.conservative {
+		for _, l := range locs {
+			s.counts[l] += count
+		}
+		return
+	}
+	// 保守更新：新的估计值为 min+count，每个计数器只需提升到该值
+	var target = s.estimate(locs) + count
+	for _, l := range locs {
+		if s.counts[l] < target {
+			s.counts[l] = target
+		}
+	}
+}
+
+// AddString 字符串元素的计数增加count
+func (s *CountMinSketch) AddString(data string, count uint64) {
+	s.Add([]byte(data), count)
+}
+
+// Estimate 元素data计数的估计值
+func (s *CountMinSketch) Estimate(data []byte) uint64 {
+	return s.estimate(s.locations(data))
+}
+
+// EstimateString 字符串元素计数的估计值
+func (s *CountMinSketch) EstimateString(data string) uint64 {
+	return s.Estimate([]byte(data))
+}
+
+func (s *CountMinSketch) estimate(locs []uint) uint64 {
+	var ans = uint64(math.MaxUint64)
+	for _, l := range locs {
+		if s.counts[l] < ans {
+			ans = s.counts[l]
+		}
+	}
+	return ans
+}
+
+// Merge 将other合并到当前sketch中，两者宽度与深度必须一致
+// 合并后的估计值仍然是真实计数的上界
+func (s *CountMinSketch) Merge(other *CountMinSketch) error {
+	if s.width != other.width || s.depth != other.depth {
+		return ErrIncompatible
+	}
+	for i := range s.counts {
+		s.counts[i] += other.counts[i]
+	}
+	s.total += other.total
+	return nil
+}
+
+// Reset 清空所有计数
+func (s *CountMinSketch) Reset() {
+	for i := range s.counts {
+		s.counts[i] = 0
+	}
+	s.total = 0
+}
@@ -0,0 +1,95 @@
+package countminsketch
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestCountMinSketch_Estimate(t *testing.T) {
+	for _, conservative := range []bool{false, true} {
+		var (
+			s     = NewWithEstimates(0.001, 0.01)
+			exact = make(map[string]uint64)
+			r     = rand.New(rand.NewSource(1))
+		)
+		if conservative {
+			s.WithConservativeUpdate()
+		}
+		for i := 0; i < 100000; i++ {
+			var key = strconv.Itoa(int(r.ExpFloat64() * 100))
+			s.AddString(key, 1)
+			exact[key]++
+		}
+		for key, cnt := range exact {
+			var got = s.EstimateString(key)
+			if got < cnt {
+				t.Fatalf("conservative=%v: EstimateString(%s) = %v, must not underestimate %v", conservative, key, got, cnt)
+			}
+			if got-cnt > uint64(0.001*float64(s.Total()))+1 {
+				t.Errorf("conservative=%v: EstimateString(%s) = %v, too far from %v", conservative, key, got, cnt)
+			}
+		}
+	}
+}
+
+func TestCountMinSketch_Merge(t *testing.T) {
+	var a, b = New(100, 4), New(100, 4)
+	a.AddString("x", 3)
+	b.AddString("x", 4)
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+	if got := a.EstimateString("x"); got < 7 {
+		t.Fatalf("EstimateString() after merge = %v, want >= 7", got)
+	}
+	if err := a.Merge(New(10, 4)); err != ErrIncompatible {
+		t.Fatalf("Merge() error = %v, want %v", err, ErrIncompatible)
+	}
+}
+
+func TestHeavyHitters(t *testing.T) {
+	var (
+		a = NewHeavyHitters(3, New(1000, 5))
+		b = NewHeavyHitters(3, New(1000, 5))
+		r = rand.New(rand.NewSource(2))
+	)
+	// 长尾噪声中混入三个高频元素
+	for i := 0; i < 20000; i++ {
+		var hh = a
+		if i%2 == 1 {
+			hh = b
+		}
+		switch i % 10 {
+		case 0, 1, 2:
+			hh.Add("hot")
+		case 3, 4:
+			hh.Add("warm")
+		case 5:
+			hh.Add("mild")
+		default:
+			hh.Add(strconv.Itoa(r.Intn(5000)))
+		}
+	}
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+	var top = a.TopK()
+	if len(top) != 3 || top[0].Key != "hot" || top[1].Key != "warm" || top[2].Key != "mild" {
+		t.Fatalf("TopK() = %v", top)
+	}
+	if top[0].Count < 6000 {
+		t.Fatalf("TopK()[0].Count = %v, want >= 6000", top[0].Count)
+	}
+}
+
+func TestHeavyHitters_LargeCounts(t *testing.T) {
+	var hh = NewHeavyHitters(2, New(1000, 5))
+	hh.AddCount("small", 10)
+	hh.AddCount("huge", 1<<63+5)
+	hh.AddCount("medium", 1000)
+	var top = hh.TopK()
+	if len(top) != 2 || top[0].Key != "huge" || top[0].Count != 1<<63+5 || top[1].Key != "medium" {
+		t.Fatalf("TopK() = %v", top)
+	}
+}
@@ -0,0 +1,10 @@
+package countminsketch
+
+import (
+	"errors"
+)
+
+var (
+	// ErrIncompatible 两个sketch的宽度或深度不一致，无法合并
+	ErrIncompatible = errors.New("count-min sketches are incompatible")
+)
@@ -0,0 +1,124 @@
+package countminsketch
+
+import (
+	"math"
+	"sort"
+
+	"github.com/1005281342/godatastructures/heap"
+)
+
+// HeavyHitter 高频元素及其估计计数
+type HeavyHitter struct {
+	Key   string
+	Count uint64
+}
+
+// HeavyHitters 在无界数据流上求近似的前K个高频元素
+// 使用Count-Min sketch估计频率，并用容量为K的有界小顶堆维护当前估计计数最大的K个元素
+type HeavyHitters struct {
+	k      int
+	sketch *CountMinSketch
+	heap   *heap.ItemHeap
+	// 堆中元素的索引，用于在计数变化时原地调整
+	items map[string]*heap.Item
+}
+
+// NewHeavyHitters 创建求前k个高频元素的结构，sketch用于频率估计
+func NewHeavyHitters(k int, sketch *CountMinSketch) *HeavyHitters {
+	if k < 1 {
+		k = 1
+	}
+	return &HeavyHitters{
+		k:      k,
+		sketch: sketch,
+		heap:   heap.NewBoundedItemHeap(k),
+		items:  make(map[string]*heap.Item, k),
+	}
+}
+
+// Sketch 底层的Count-Min sketch
+func (h *HeavyHitters) Sketch() *CountMinSketch {
+	return h.sketch
+}
+
+// Add 元素key出现一次
+func (h *HeavyHitters) Add(key string) {
+	h.AddCount(key, 1)
+}
+
+// AddCount 元素key出现count次
+func (h *HeavyHitters) AddCount(key string, count uint64) {
+	h.sketch.AddString(key, count)
+	h.offer(key, h.sketch.EstimateString(key))
+}
+
+// priority 估计计数对应的堆优先级，超过int64范围时饱和为math.MaxInt64
+func priority(estimate uint64) int64 {
+	if estimate > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return int64(estimate)
+}
+
+// offer 用key的最新估计计数更新堆
+func (h *HeavyHitters) offer(key string, estimate uint64) {
+	if item, has := h.items[key]; has {
+		item.Priority = priority(estimate)
+		h.heap.Fix(item)
+		return
+	}
+	var item = heap.NewItem(key, priority(estimate))
+	if evicted, ok := h.heap.Offer(item); ok {
+		delete(h.items, evicted.Value.(string))
+		if evicted == item {
+			return
+		}
+	}
+	h.items[key] = item
+}
+
+// TopK 按估计计数从大到小返回当前的前K个高频元素
+func (h *HeavyHitters) TopK() []HeavyHitter {
+	var ans = make([]HeavyHitter, 0, h.heap.Len())
+	for _, item := range h.heap.Items() {
+		var (
+			key   = item.Value.(string)
+			count = uint64(item.Priority)
+		)
+		if item.Priority == math.MaxInt64 {
+			// 优先级已饱和，从sketch中取回完整的估计计数
+			count = h.sketch.EstimateString(key)
+		}
+		ans = append(ans, HeavyHitter{Key: key, Count: count})
+	}
+	sort.Slice(ans, func(i, j int) bool {
+		if ans[i].Count != ans[j].Count {
+			return ans[i].Count > ans[j].Count
+		}
+		return ans[i].Key < ans[j].Key
+	})
+	return ans
+}
+
+// Merge 合并另一个数据流的结果，两者的sketch必须兼容
+// 合并后以两边候选元素在合并后sketch中的估计计数重新选出前K个
+func (h *HeavyHitters) Merge(other *HeavyHitters) error {
+	if err := h.sketch.Merge(other.sketch); err != nil {
+		return err
+	}
+	var candidates = make([]string, 0, len(h.items)+len(other.items))
+	for key := range h.items {
+		candidates = append(candidates, key)
+	}
+	for key := range other.items {
+		if _, has := h.items[key]; !has {
+			candidates = append(candidates, key)
+		}
+	}
+	h.heap = heap.NewBoundedItemHeap(h.k)
+	h.items = make(map[string]*heap.Item, h.k)
+	for _, key := range candidates {
+		h.offer(key, h.sketch.EstimateString(key))
+	}
+	return nil
+}
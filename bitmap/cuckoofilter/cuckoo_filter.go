@@ -0,0 +1,392 @@
+package cuckoofilter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/fnv"
+	"io"
+	"math/rand"
+
+	"github.com/1005281342/godatastructures/bitmap/internal/hashutil"
+)
+
+const (
+	// DefaultFingerprintBits 默认指纹位数
+	DefaultFingerprintBits = 16
+	// DefaultBucketSize 默认每个桶的槽位数
+	DefaultBucketSize = 4
+	// DefaultMaxKicks 默认最大踢出次数
+	DefaultMaxKicks = 500
+	// MaxTableWords 反序列化时允许的最大槽位表字数
+	MaxTableWords = 1 << 32
+	// readChunkWords 反序列化时每次读取的字数
+	readChunkWords = 512
+)
+
+// victim 踢出次数达到上限时无处安放的指纹
+type victim struct {
+	used        bool
+	index       uint64
+	fingerprint uint32
+}
+
+// CuckooFilter 布谷鸟过滤器
+// 每个元素只保存一个指纹，存放在两个候选桶之一，支持删除，在低误判率下比布隆过滤器更省空间
+type CuckooFilter struct {
+	// 指纹位数
+	fpBits uint
+	// 每个桶的槽位数
+	bucketSize uint
+	// 桶个数，为2的幂
+	numBuckets uint64
+	// 最大踢出次数
+	maxKicks uint
+	// 已存放的指纹个数
+	count uint
+	// 按fpBits紧凑存放的槽位，指纹为0表示空槽位
+	table  []uint64
+	victim victim
+	rnd    *rand.Rand
+}
+
+// NewCuckooFilter 创建能容纳约capacity个元素的布谷鸟过滤器
+// fingerprintBits取值[4, 32]，越大误判率越低；bucketSize取值[1, 16]，越大装载率越高
+func NewCuckooFilter(capacity, fingerprintBits, bucketSize uint) (*CuckooFilter, error) {
+	if fingerprintBits < 4 || fingerprintBits > 32 || bucketSize < 1 || bucketSize > 16 {
+		return nil, ErrInvalidConfig
+	}
+	var numBuckets = uint64(1)
+	for numBuckets*uint64(bucketSize) < uint64(capacity) {
+		numBuckets <<= 1
+	}
+	var f = &CuckooFilter{
+		fpBits:     fingerprintBits,
+		bucketSize: bucketSize,
+		numBuckets: numBuckets,
+		maxKicks:   DefaultMaxKicks,
+		rnd:        rand.New(rand.NewSource(int64(numBuckets))),
+	}
+	f.table = make([]uint64, f.tableWords())
+	return f, nil
+}
+
+// WithMaxKicks 指定插入时的最大踢出次数
+func (f *CuckooFilter) WithMaxKicks(n uint) {
+	f.maxKicks = n
+}
+
+func (f *CuckooFilter) tableWords() uint64 {
+	return (f.numBuckets*uint64(f.bucketSize)*uint64(f.fpBits) + 63) / 64
+}
+
+// get 第slot个槽位的指纹
+func (f *CuckooFilter) get(slot uint64) uint32 {
+	var (
+		pos = slot * uint64(f.fpBits)
+		w   = pos / 64
+		off = pos % 64
+		v   = f.table[w] >> off
+	)
+	if off+uint64(f.fpBits) > 64 {
+		// 跨越两个字
+		v |= f.table[w+1] << (64 - off)
+	}
+	return uint32(v & (1<<f.fpBits - 1))
+}
+
+// set 设置第slot个槽位的指纹
+func (f *CuckooFilter) set(slot uint64, fp uint32) {
+	var (
+		pos  = slot * uint64(f.fpBits)
+		w    = pos / 64
+		off  = pos % 64
+		mask = uint64(1)<<f.fpBits - 1
+	)
+	f.table[w] = f.table[w]&^(mask<<off) | uint64(fp)<<off
+	if off+uint64(f.fpBits) > 64 {
+		var shift = 64 - off
+		f.table[w+1] = f.table[w+1]&^(mask>>shift) | uint64(fp)>>shift
+	}
+}
+
+// indexAndFingerprint 计算data的第一个候选桶与指纹
+func (f *CuckooFilter) indexAndFingerprint(data []byte) (uint64, uint32) {
+	var h = fnv.New64a()
+	_, _ = h.Write(data)
+	var (
+		sum = hashutil.Mix64(h.Sum64())
+		fp  = uint32(sum>>32) & (1<<f.fpBits - 1)
+	)
+	// 指纹0表示空槽位，需要避开
+	if fp == 0 {
+		fp = 1
+	}
+	return sum & (f.numBuckets - 1), fp
+}
+
+// altIndex 另一个候选桶，i1 = altIndex(i2, fp)同样成立
+func (f *CuckooFilter) altIndex(i uint64, fp uint32) uint64 {
+	return (i ^ hashutil.Mix64(uint64(fp))) & (f.numBuckets - 1)
+}
+
+// bucketContains 桶i中是否存在指纹fp
+func (f *CuckooFilter) bucketContains(i uint64, fp uint32) bool {
+	for j := uint64(0); j < uint64(f.bucketSize); j++ {
+		if f.get(i*uint64(f.bucketSize)+j) == fp {
+			return true
+		}
+	}
+	return false
+}
+
+// bucketInsert 在桶i中找空槽位存放指纹fp
+func (f *CuckooFilter) bucketInsert(i uint64, fp uint32) bool {
+	for j := uint64(0); j < uint64(f.bucketSize); j++ {
+		if f.get(i*uint64(f.bucketSize)+j) == 0 {
+			f.set(i*uint64(f.bucketSize)+j, fp)
+			return true
+		}
+	}
+	return false
+}
+
+// bucketDelete 从桶i中删除一个指纹fp
+func (f *CuckooFilter) bucketDelete(i uint64, fp uint32) bool {
+	for j := uint64(0); j < uint64(f.bucketSize); j++ {
+		if f.get(i*uint64(f.bucketSize)+j) == fp {
+			f.set(i*uint64(f.bucketSize)+j, 0)
+			return true
+		}
+	}
+	return false
+}
+
+// Insert 插入元素data，过滤器已满时返回ErrFilterFull
+// 同一元素可以重复插入，每个桶对最多容纳2*bucketSize个相同指纹
+func (f *CuckooFilter) Insert(data []byte) error {
+	if f.victim.used {
+		return ErrFilterFull
+	}
+	var (
+		i1, fp = f.indexAndFingerprint(data)
+		i2     = f.altIndex(i1, fp)
+	)
+	if f.bucketInsert(i1, fp) || f.bucketInsert(i2, fp) {
+		f.count++
+		return nil
+	}
+	// 两个候选桶都满了，随机踢出一个指纹并将其移到它的另一个候选桶
+	var i = i1
+	if f.rnd.Intn(2) == 1 {
+		i = i2
+	}
+	for n := uint(0); n < f.maxKicks; n++ {
+		var slot = i*uint64(f.bucketSize) + uint64(f.rnd.Intn(int(f.bucketSize)))
+		var old = f.get(slot)
+		f.set(slot, fp)
+		fp = old
+		i = f.altIndex(i, fp)
+		if f.bucketInsert(i, fp) {
+			f.count++
+			return nil
+		}
+	}
+	// 将最后被踢出的指纹放入备用槽位，保证已插入的元素不会丢失
+	f.victim = victim{used: true, index: i, fingerprint: fp}
+	f.count++
+	return nil
+}
+
+// InsertString 插入字符串元素
+func (f *CuckooFilter) InsertString(data string) error {
+	return f.Insert([]byte(data))
+}
+
+// Lookup 元素data是否可能存在
+func (f *CuckooFilter) Lookup(data []byte) bool {
+	var (
+		i1, fp = f.indexAndFingerprint(data)
+		i2     = f.altIndex(i1, fp)
+	)
+	if f.victim.used && f.victim.fingerprint == fp && (f.victim.index == i1 || f.victim.index == i2) {
+		return true
+	}
+	return f.bucketContains(i1, fp) || f.bucketContains(i2, fp)
+}
+
+// LookupString 字符串元素是否可能存在
+func (f *CuckooFilter) LookupString(data string) bool {
+	return f.Lookup([]byte(data))
+}
+
+// Delete 删除元素data，不存在时返回false
+// 只应删除确实插入过的元素，否则可能误删指纹相同的其他元素
+func (f *CuckooFilter) Delete(data []byte) bool {
+	var (
+		i1, fp = f.indexAndFingerprint(data)
+		i2     = f.altIndex(i1, fp)
+	)
+	if f.victim.used && f.victim.fingerprint == fp && (f.victim.index == i1 || f.victim.index == i2) {
+		f.victim = victim{}
+		f.count--
+		return true
+	}
+	if f.bucketDelete(i1, fp) || f.bucketDelete(i2, fp) {
+		f.count--
+		f.reinsertVictim()
+		return true
+	}
+	return false
+}
+
+// DeleteString 删除字符串元素
+func (f *CuckooFilter) DeleteString(data string) bool {
+	return f.Delete([]byte(data))
+}
+
+// reinsertVictim 删除后腾出了空间，尝试将备用槽位的指纹放回桶中
+func (f *CuckooFilter) reinsertVictim() {
+	if !f.victim.used {
+		return
+	}
+	var (
+		i  = f.victim.index
+		fp = f.victim.fingerprint
+	)
+	if f.bucketInsert(i, fp) || f.bucketInsert(f.altIndex(i, fp), fp) {
+		f.victim = victim{}
+	}
+}
+
+// Count 已插入的元素个数
+func (f *CuckooFilter) Count() uint {
+	return f.count
+}
+
+// Capacity 槽位总数
+func (f *CuckooFilter) Capacity() uint {
+	return uint(f.numBuckets) * f.bucketSize
+}
+
+// LoadFactor 装载率，即已使用槽位的比例
+func (f *CuckooFilter) LoadFactor() float64 {
+	return float64(f.count) / float64(f.Capacity())
+}
+
+// Reset 清空过滤器
+func (f *CuckooFilter) Reset() {
+	for i := range f.table {
+		f.table[i] = 0
+	}
+	f.victim = victim{}
+	f.count = 0
+}
+
+// header 序列化头部：指纹位数、桶大小、最大踢出次数、桶个数、元素个数、备用槽位
+type header struct {
+	FpBits     uint8
+	BucketSize uint8
+	VictimUsed uint8
+	_          uint8
+	MaxKicks   uint32
+	NumBuckets uint64
+	Count      uint64
+	VictimIdx  uint64
+	VictimFp   uint32
+}
+
+// WriteTo 序列化到w，所有字段均为大端
+func (f *CuckooFilter) WriteTo(w io.Writer) (int64, error) {
+	var h = header{
+		FpBits:     uint8(f.fpBits),
+		BucketSize: uint8(f.bucketSize),
+		MaxKicks:   uint32(f.maxKicks),
+		NumBuckets: f.numBuckets,
+		Count:      uint64(f.count),
+		VictimIdx:  f.victim.index,
+		VictimFp:   f.victim.fingerprint,
+	}
+	if f.victim.used {
+		h.VictimUsed = 1
+	}
+	var buf = bytes.NewBuffer(make([]byte, 0, binary.Size(h)+8*len(f.table)))
+	_ = binary.Write(buf, binary.BigEndian, h)
+	_ = binary.Write(buf, binary.BigEndian, f.table)
+	var n, err = w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// ReadFrom 从r中反序列化，覆盖当前内容
+func (f *CuckooFilter) ReadFrom(r io.Reader) (int64, error) {
+	var h header
+	if err := binary.Read(r, binary.BigEndian, &h); err != nil {
+		return 0, err
+	}
+	var n = int64(binary.Size(h))
+	if !h.valid() {
+		return n, ErrInvalidData
+	}
+	var g = &CuckooFilter{
+		fpBits:     uint(h.FpBits),
+		bucketSize: uint(h.BucketSize),
+		numBuckets: h.NumBuckets,
+		maxKicks:   uint(h.MaxKicks),
+		count:      uint(h.Count),
+		victim:     victim{used: h.VictimUsed == 1, index: h.VictimIdx, fingerprint: h.VictimFp},
+		rnd:        rand.New(rand.NewSource(int64(h.NumBuckets))),
+	}
+	// 按块读取，避免按头部中的桶个数预先分配内存
+	var (
+		words = g.tableWords()
+		buf   = make([]uint64, readChunkWords)
+	)
+	for uint64(len(g.table)) < words {
+		var chunk = buf
+		if rest := words - uint64(len(g.table)); rest < readChunkWords {
+			chunk = buf[:rest]
+		}
+		if err := binary.Read(r, binary.BigEndian, chunk); err != nil {
+			return n + int64(8*len(g.table)), err
+		}
+		g.table = append(g.table, chunk...)
+	}
+	*f = *g
+	return n + int64(8*len(g.table)), nil
+}
+
+// valid 头部各字段是否合法，桶个数须为2的幂且槽位表不超过MaxTableWords
+func (h *header) valid() bool {
+	if h.FpBits < 4 || h.FpBits > 32 || h.BucketSize < 1 || h.BucketSize > 16 ||
+		h.NumBuckets == 0 || h.NumBuckets&(h.NumBuckets-1) != 0 {
+		return false
+	}
+	// 先限制桶个数，避免槽位数与位数的乘积溢出
+	if h.NumBuckets > MaxTableWords*64/(uint64(h.BucketSize)*uint64(h.FpBits)) {
+		return false
+	}
+	var slots = h.NumBuckets * uint64(h.BucketSize)
+	switch h.VictimUsed {
+	case 0:
+		return h.Count <= slots
+	case 1:
+		return h.Count <= slots+1 && h.VictimIdx < h.NumBuckets &&
+			h.VictimFp != 0 && h.VictimFp <= 1<<h.FpBits-1
+	default:
+		return false
+	}
+}
+
+// MarshalBinary 实现encoding.BinaryMarshaler
+func (f *CuckooFilter) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := f.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary 实现encoding.BinaryUnmarshaler
+func (f *CuckooFilter) UnmarshalBinary(data []byte) error {
+	var _, err = f.ReadFrom(bytes.NewReader(data))
+	return err
+}
@@ -0,0 +1,161 @@
+package cuckoofilter
+
+import (
+	"encoding/binary"
+	"io"
+	"strconv"
+	"testing"
+)
+
+func TestCuckooFilter_InsertLookupDelete(t *testing.T) {
+	for _, fpBits := range []uint{8, 12, 16, 32} {
+		var f, err = NewCuckooFilter(10000, fpBits, DefaultBucketSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 9000; i++ {
+			if err = f.InsertString(strconv.Itoa(i)); err != nil {
+				t.Fatalf("fpBits %d: InsertString(%d) error = %v", fpBits, i, err)
+			}
+		}
+		for i := 0; i < 9000; i++ {
+			if !f.LookupString(strconv.Itoa(i)) {
+				t.Fatalf("fpBits %d: LookupString(%d) = false, want true", fpBits, i)
+			}
+		}
+		for i := 0; i < 4500; i++ {
+			if !f.DeleteString(strconv.Itoa(i)) {
+				t.Fatalf("fpBits %d: DeleteString(%d) = false, want true", fpBits, i)
+			}
+		}
+		for i := 4500; i < 9000; i++ {
+			if !f.LookupString(strconv.Itoa(i)) {
+				t.Fatalf("fpBits %d: LookupString(%d) = false after deleting other keys", fpBits, i)
+			}
+		}
+		if f.Count() != 4500 {
+			t.Fatalf("fpBits %d: Count() = %v, want %v", fpBits, f.Count(), 4500)
+		}
+		if fpBits == 16 {
+			var fp int
+			for i := 100000; i < 110000; i++ {
+				if f.LookupString(strconv.Itoa(i)) {
+					fp++
+				}
+			}
+			if fp > 10 {
+				t.Errorf("fpBits 16: %d false positives in 10000 lookups", fp)
+			}
+		}
+	}
+}
+
+func TestCuckooFilter_Full(t *testing.T) {
+	var f, _ = NewCuckooFilter(64, 8, 2)
+	f.WithMaxKicks(10)
+	var (
+		err error
+		n   int
+	)
+	for ; err == nil; n++ {
+		err = f.InsertString(strconv.Itoa(n))
+	}
+	if err != ErrFilterFull {
+		t.Fatalf("Insert() error = %v, want %v", err, ErrFilterFull)
+	}
+	// 报错之前插入的元素都不能丢失
+	for i := 0; i < n-1; i++ {
+		if !f.LookupString(strconv.Itoa(i)) {
+			t.Fatalf("LookupString(%d) = false, want true", i)
+		}
+	}
+	if lf := f.LoadFactor(); lf <= 0.5 || lf > 1.01 {
+		t.Fatalf("LoadFactor() = %v", lf)
+	}
+	if _, err = NewCuckooFilter(64, 2, 4); err != ErrInvalidConfig {
+		t.Fatalf("NewCuckooFilter() error = %v, want %v", err, ErrInvalidConfig)
+	}
+}
+
+func TestCuckooFilter_Marshal(t *testing.T) {
+	var f, _ = NewCuckooFilter(1000, 12, 4)
+	for i := 0; i < 500; i++ {
+		_ = f.InsertString(strconv.Itoa(i))
+	}
+	var data, err = f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var g = new(CuckooFilter)
+	if err = g.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if g.Count() != f.Count() || g.LoadFactor() != f.LoadFactor() {
+		t.Fatal("unmarshaled filter differs from the original")
+	}
+	for i := 0; i < 500; i++ {
+		if !g.LookupString(strconv.Itoa(i)) {
+			t.Fatalf("LookupString(%d) = false after unmarshal", i)
+		}
+	}
+	if err = g.UnmarshalBinary(data[:20]); err == nil {
+		t.Fatal("UnmarshalBinary() on truncated data should fail")
+	}
+}
+
+func TestCuckooFilter_ReadFromInvalid(t *testing.T) {
+	var f, _ = NewCuckooFilter(64, DefaultFingerprintBits, DefaultBucketSize)
+	_ = f.InsertString("a")
+	var valid, _ = f.MarshalBinary()
+	// 头部偏移：0 FpBits, 1 BucketSize, 2 VictimUsed, 8 NumBuckets, 16 Count, 24 VictimIdx, 32 VictimFp
+	var corrupt = func(fn func(data []byte)) []byte {
+		var data = append([]byte(nil), valid...)
+		fn(data)
+		return data
+	}
+	var tests = []struct {
+		name string
+		data []byte
+	}{
+		{"fingerprint bits", corrupt(func(d []byte) { d[0] = 33 })},
+		{"bucket size", corrupt(func(d []byte) { d[1] = 0 })},
+		{"victim flag", corrupt(func(d []byte) { d[2] = 2 })},
+		{"buckets not power of two", corrupt(func(d []byte) { binary.BigEndian.PutUint64(d[8:], 12) })},
+		{"overflowing buckets", corrupt(func(d []byte) { binary.BigEndian.PutUint64(d[8:], 1<<62) })},
+		{"oversized table", corrupt(func(d []byte) { binary.BigEndian.PutUint64(d[8:], 1<<40) })},
+		{"count", corrupt(func(d []byte) { binary.BigEndian.PutUint64(d[16:], 1000) })},
+		{"victim index", corrupt(func(d []byte) {
+			d[2] = 1
+			binary.BigEndian.PutUint64(d[24:], 1<<20)
+			binary.BigEndian.PutUint32(d[32:], 7)
+		})},
+		{"victim fingerprint", corrupt(func(d []byte) {
+			d[2] = 1
+			binary.BigEndian.PutUint32(d[32:], 1<<DefaultFingerprintBits)
+		})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := new(CuckooFilter).UnmarshalBinary(tt.data); err != ErrInvalidData {
+				t.Fatalf("UnmarshalBinary() error = %v, want %v", err, ErrInvalidData)
+			}
+		})
+	}
+	// 头部声称的桶个数合法但数据不足时，不应预先分配整个槽位表
+	var short = corrupt(func(d []byte) { binary.BigEndian.PutUint64(d[8:], 1<<30) })
+	if err := new(CuckooFilter).UnmarshalBinary(short); err != io.ErrUnexpectedEOF {
+		t.Fatalf("UnmarshalBinary() error = %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+	// 合法的备用槽位在删除后可以放回桶中
+	var g = new(CuckooFilter)
+	if err := g.UnmarshalBinary(corrupt(func(d []byte) {
+		d[2] = 1
+		binary.BigEndian.PutUint64(d[24:], 3)
+		binary.BigEndian.PutUint32(d[32:], 7)
+	})); err != nil {
+		t.Fatal(err)
+	}
+	if !g.DeleteString("a") {
+		t.Fatal(`DeleteString("a") = false`)
+	}
+}
@@ -0,0 +1,14 @@
+package cuckoofilter
+
+import (
+	"errors"
+)
+
+var (
+	// ErrFilterFull 踢出次数达到上限且备用槽位已被占用，无法再插入
+	ErrFilterFull = errors.New("cuckoo filter is full")
+	// ErrInvalidConfig 指纹位数或桶大小不合法
+	ErrInvalidConfig = errors.New("fingerprint bits must be in [4, 32] and bucket size in [1, 16]")
+	// ErrInvalidData 反序列化的数据不合法
+	ErrInvalidData = errors.New("invalid cuckoo filter data")
+)
@@ -0,0 +1,14 @@
+package hyperloglog
+
+import (
+	"errors"
+)
+
+var (
+	// ErrPrecision 精度超出[4, 18]
+	ErrPrecision = errors.New("hyperloglog: precision must be in [4, 18]")
+	// ErrPrecisionMismatch 精度不同的两个HyperLogLog无法合并
+	ErrPrecisionMismatch = errors.New("hyperloglog: precisions do not match")
+	// ErrInvalidData 反序列化的数据不合法
+	ErrInvalidData = errors.New("hyperloglog: invalid data")
+)
@@ -0,0 +1,347 @@
+package hyperloglog
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"sort"
+
+	"github.com/1005281342/godatastructures/bitmap/internal/hashutil"
+)
+
+const (
+	// MinPrecision 最小精度
+	MinPrecision = 4
+	// MaxPrecision 最大精度
+	MaxPrecision = 18
+	// sparsePrecision 稀疏表示使用的精度p'
+	sparsePrecision = 25
+	// version 序列化格式版本
+	version = 1
+)
+
+// HyperLogLog 基数估计
+// 采用HLL++的64位哈希与稀疏表示：基数较小时以精度p'=25的有序编码列表存放，并用线性计数估计，
+// 超过阈值后转换为2^p个寄存器的稠密表示；稠密表示使用HLL++的经验偏差修正，
+// 原始估计值不超过5m时按经验数据的k近邻插值减去偏差，基数较小时按各精度的阈值切换到线性计数
+type HyperLogLog struct {
+	// 精度，寄存器个数为2^p
+	p uint8
+	// 是否为稀疏表示
+	sparse bool
+	// 稀疏表示的有序列表，每项为 index'<<6 | rho'，index'相同的只保留rho'最大的一项
+	sparseList []uint32
+	// 尚未合并到sparseList的临时编码
+	tmp []uint32
+	// 稠密表示的寄存器
+	registers []uint8
+}
+
+// New 创建精度为precision的HyperLogLog，标准误差约为1.04/sqrt(2^precision)
+func New(precision uint8) (*HyperLogLog, error) {
+	if precision < MinPrecision || precision > MaxPrecision {
+		return nil, ErrPrecision
+	}
+	return &HyperLogLog{p: precision, sparse: true}, nil
+}
+
+// Precision 精度
+func (h *HyperLogLog) Precision() uint8 {
+	return h.p
+}
+
+// m 寄存器个数
+func (h *HyperLogLog) m() uint32 {
+	return 1 << h.p
+}
+
+// Add 添加元素data
+func (h *HyperLogLog) Add(data []byte) {
+	var f = fnv.New64a()
+	_, _ = f.Write(data)
+	h.AddHash(hashutil.Mix64(f.Sum64()))
+}
+
+// AddString 添加字符串元素
+func (h *HyperLogLog) AddString(data string) {
+	h.Add([]byte(data))
+}
+
+// AddHash 添加一个已经过均匀哈希的64位值
+func (h *HyperLogLog) AddHash(x uint64) {
+	if !h.sparse {
+		h.addDense(x)
+		return
+	}
+	h.tmp = append(h.tmp, encodeSparse(x))
+	// 临时列表过长时合并，稀疏列表过长时转为稠密表示
+	if len(h.tmp) > h.sparseThreshold()/4+16 {
+		h.mergeTmp()
+		if len(h.sparseList) > h.sparseThreshold() {
+			h.toDense()
+		}
+	}
+}
+
+// sparseThreshold 稀疏列表每项4字节，超过稠密表示的字节数（每个寄存器1字节）时转换
+func (h *HyperLogLog) sparseThreshold() int {
+	return int(h.m()) / 4
+}
+
+// encodeSparse 按精度p'编码：index'<<6 | rho'
+func encodeSparse(x uint64) uint32 {
+	var (
+		idx = uint32(x >> (64 - sparsePrecision))
+		rho = uint32(bits.LeadingZeros64(x<<sparsePrecision|1<<(sparsePrecision-1))) + 1
+	)
+	return idx<<6 | rho
+}
+
+// decodeSparse 将精度p'的编码转换为精度p的寄存器下标与值
+func (h *HyperLogLog) decodeSparse(e uint32) (uint32, uint8) {
+	var (
+		idx  = e >> 6
+		rho  = uint8(e & 0x3f)
+		diff = uint(sparsePrecision - h.p)
+		// index'中位于p之后的diff位
+		low = idx & (1<<diff - 1)
+	)
+	if low != 0 {
+		return idx >> diff, uint8(bits.LeadingZeros32(low<<(32-diff))) + 1
+	}
+	return idx >> diff, uint8(diff) + rho
+}
+
+// addDense 更新稠密表示的寄存器
+func (h *HyperLogLog) addDense(x uint64) {
+	var (
+		idx = x >> (64 - h.p)
+		rho = uint8(bits.LeadingZeros64(x<<h.p|1<<(h.p-1))) + 1
+	)
+	if rho > h.registers[idx] {
+		h.registers[idx] = rho
+	}
+}
+
+// mergeTmp 将临时编码合并到有序稀疏列表中
+func (h *HyperLogLog) mergeTmp() {
+	if len(h.tmp) == 0 {
+		return
+	}
+	var all = append(h.sparseList, h.tmp...)
+	h.sparseList = dedupSparse(all)
+	h.tmp = h.tmp[:0]
+}
+
+// dedupSparse 排序并对相同index'只保留rho'最大的一项
+func dedupSparse(list []uint32) []uint32 {
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+	var n int
+	for _, e := range list {
+		// 排序后相同index'中rho'最大的排在最后，覆盖前面的项
+		if n > 0 && list[n-1]>>6 == e>>6 {
+			list[n-1] = e
+			continue
+		}
+		list[n] = e
+		n++
+	}
+	return list[:n]
+}
+
+// toDense 转换为稠密表示
+func (h *HyperLogLog) toDense() {
+	h.mergeTmp()
+	h.registers = make([]uint8, h.m())
+	for _, e := range h.sparseList {
+		var idx, rho = h.decodeSparse(e)
+		if rho > h.registers[idx] {
+			h.registers[idx] = rho
+		}
+	}
+	h.sparse, h.sparseList, h.tmp = false, nil, nil
+}
+
+// Count 估计的基数
+func (h *HyperLogLog) Count() uint64 {
+	if h.sparse {
+		h.mergeTmp()
+		// 稀疏表示时使用线性计数，m'=2^25远大于元素个数，误差很小
+		var mp = float64(uint64(1) << sparsePrecision)
+		return uint64(math.Round(linearCounting(mp, mp-float64(len(h.sparseList)))))
+	}
+	return uint64(math.Round(h.estimateDense()))
+}
+
+// estimateDense HLL++的稠密估计：原始估计值不超过5m时减去经验偏差，
+// 存在空寄存器且线性计数不超过该精度的阈值时改用线性计数
+func (h *HyperLogLog) estimateDense() float64 {
+	var (
+		m     = float64(h.m())
+		sum   float64
+		zeros float64
+	)
+	for _, r := range h.registers {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+	var e = alpha(h.m()) * m * m / sum
+	if e <= 5*m {
+		e -= h.estimateBias(e)
+	}
+	if zeros > 0 {
+		if lc := linearCounting(m, zeros); lc <= thresholds[h.p-MinPrecision] {
+			return lc
+		}
+	}
+	return e
+}
+
+// alpha 原始估计值的修正系数
+func alpha(m uint32) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	}
+	return 0.7213 / (1 + 1.079/float64(m))
+}
+
+// linearCounting m个桶中有v个为空时的线性计数估计
+func linearCounting(m, v float64) float64 {
+	return m * math.Log(m/v)
+}
+
+// biasNeighbors 估计偏差时取的近邻个数
+const biasNeighbors = 6
+
+// estimateBias 在经验数据中取与原始估计值e最接近的6个采样点，以其偏差的平均值作为e的偏差
+func (h *HyperLogLog) estimateBias(e float64) float64 {
+	var (
+		raw  = rawEstimateData[h.p-MinPrecision]
+		bias = biasData[h.p-MinPrecision]
+		// [lo, hi)为已选中的近邻
+		hi  = sort.SearchFloat64s(raw, e)
+		lo  = hi
+		sum float64
+	)
+	for hi-lo < biasNeighbors {
+		if lo > 0 && (hi == len(raw) || e-raw[lo-1] <= raw[hi]-e) {
+			lo--
+			sum += bias[lo]
+		} else {
+			sum += bias[hi]
+			hi++
+		}
+	}
+	return sum / biasNeighbors
+}
+
+// Merge 将other合并到当前HyperLogLog中，两者精度必须相同
+func (h *HyperLogLog) Merge(other *HyperLogLog) error {
+	if h.p != other.p {
+		return ErrPrecisionMismatch
+	}
+	if h.sparse && other.sparse {
+		h.tmp = append(h.tmp, other.sparseList...)
+		h.tmp = append(h.tmp, other.tmp...)
+		h.mergeTmp()
+		if len(h.sparseList) > h.sparseThreshold() {
+			h.toDense()
+		}
+		return nil
+	}
+	if h.sparse {
+		h.toDense()
+	}
+	if other.sparse {
+		for _, list := range [][]uint32{other.sparseList, other.tmp} {
+			for _, e := range list {
+				var idx, rho = h.decodeSparse(e)
+				if rho > h.registers[idx] {
+					h.registers[idx] = rho
+				}
+			}
+		}
+		return nil
+	}
+	for i, r := range other.registers {
+		if r > h.registers[i] {
+			h.registers[i] = r
+		}
+	}
+	return nil
+}
+
+// Clone 深拷贝
+func (h *HyperLogLog) Clone() *HyperLogLog {
+	var c = &HyperLogLog{p: h.p, sparse: h.sparse}
+	c.sparseList = append([]uint32(nil), h.sparseList...)
+	c.tmp = append([]uint32(nil), h.tmp...)
+	if h.registers != nil {
+		c.registers = append([]uint8(nil), h.registers...)
+	}
+	return c
+}
+
+// MarshalBinary 实现encoding.BinaryMarshaler
+// 格式：版本、精度、是否稀疏，稀疏时为4字节项数与每项4字节编码（大端），稠密时为2^p个寄存器
+func (h *HyperLogLog) MarshalBinary() ([]byte, error) {
+	if !h.sparse {
+		var data = make([]byte, 3, 3+len(h.registers))
+		data[0], data[1], data[2] = version, h.p, 0
+		return append(data, h.registers...), nil
+	}
+	h.mergeTmp()
+	var data = make([]byte, 7+4*len(h.sparseList))
+	data[0], data[1], data[2] = version, h.p, 1
+	binary.BigEndian.PutUint32(data[3:], uint32(len(h.sparseList)))
+	for i, e := range h.sparseList {
+		binary.BigEndian.PutUint32(data[7+4*i:], e)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary 实现encoding.BinaryUnmarshaler
+func (h *HyperLogLog) UnmarshalBinary(data []byte) error {
+	if len(data) < 3 || data[0] != version || data[1] < MinPrecision || data[1] > MaxPrecision {
+		return ErrInvalidData
+	}
+	var g = &HyperLogLog{p: data[1], sparse: data[2] == 1}
+	if !g.sparse {
+		if len(data) != 3+int(g.m()) {
+			return ErrInvalidData
+		}
+		g.registers = append([]uint8(nil), data[3:]...)
+		for _, r := range g.registers {
+			if r > 65-g.p {
+				return ErrInvalidData
+			}
+		}
+		*h = *g
+		return nil
+	}
+	if len(data) < 7 {
+		return ErrInvalidData
+	}
+	var n = int(binary.BigEndian.Uint32(data[3:]))
+	if len(data) != 7+4*n {
+		return ErrInvalidData
+	}
+	g.sparseList = make([]uint32, n)
+	for i := range g.sparseList {
+		g.sparseList[i] = binary.BigEndian.Uint32(data[7+4*i:])
+		if g.sparseList[i]&0x3f == 0 || g.sparseList[i]&0x3f > 64-sparsePrecision+1 {
+			return ErrInvalidData
+		}
+	}
+	g.sparseList = dedupSparse(g.sparseList)
+	*h = *g
+	return nil
+}
@@ -0,0 +1,108 @@
+package hyperloglog
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestHyperLogLog_Count(t *testing.T) {
+	for _, n := range []int{0, 1, 10, 100, 1000, 10000, 100000, 1000000} {
+		var h, _ = New(14)
+		for i := 0; i < n; i++ {
+			h.AddString(strconv.Itoa(i))
+			// 重复元素不影响基数
+			h.AddString(strconv.Itoa(i / 2))
+		}
+		var (
+			got = float64(h.Count())
+			err = math.Abs(got-float64(n)) / math.Max(float64(n), 1)
+		)
+		// 精度14的标准误差约为0.8%
+		if err > 0.03 {
+			t.Errorf("n=%d: Count() = %v, relative error %.4f", n, got, err)
+		}
+	}
+}
+
+func TestHyperLogLog_BiasCorrection(t *testing.T) {
+	// 稠密表示下原始估计值不超过5m的区间需要经验偏差修正，取多个独立样本的平均相对误差
+	const trials = 10
+	tests := []struct {
+		p uint8
+		n int
+	}{
+		{p: 6, n: 100}, {p: 6, n: 250}, {p: 10, n: 2000}, {p: 10, n: 4000}, {p: 14, n: 30000}, {p: 14, n: 70000},
+	}
+	for _, tt := range tests {
+		var sum float64
+		for trial := 0; trial < trials; trial++ {
+			var h, _ = New(tt.p)
+			h.toDense()
+			for i := 0; i < tt.n; i++ {
+				h.AddString(strconv.Itoa(trial) + "-" + strconv.Itoa(i))
+			}
+			sum += (float64(h.Count()) - float64(tt.n)) / float64(tt.n)
+		}
+		var (
+			mean = sum / trials
+			// 平均值的标准误差约为1.04/sqrt(m*trials)
+			limit = 3 * 1.04 / math.Sqrt(float64(uint64(1)<<tt.p)*trials)
+		)
+		if math.Abs(mean) > limit {
+			t.Errorf("p=%d n=%d: mean relative error %.4f, want within %.4f", tt.p, tt.n, mean, limit)
+		}
+	}
+}
+
+func TestHyperLogLog_Merge(t *testing.T) {
+	var (
+		a, _ = New(12)
+		b, _ = New(12)
+		c, _ = New(12)
+	)
+	for i := 0; i < 50000; i++ {
+		a.AddString(strconv.Itoa(i))
+	}
+	// b保持稀疏表示，覆盖稠密与稀疏的合并
+	for i := 40000; i < 40300; i++ {
+		b.AddString(strconv.Itoa(i))
+		c.AddString(strconv.Itoa(i + 100000))
+	}
+	if err := b.Merge(c); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+	if got := float64(a.Count()); math.Abs(got-50300)/50300 > 0.05 {
+		t.Errorf("Count() after merge = %v, want about 50300", got)
+	}
+	var d, _ = New(10)
+	if err := a.Merge(d); err != ErrPrecisionMismatch {
+		t.Fatalf("Merge() error = %v, want %v", err, ErrPrecisionMismatch)
+	}
+}
+
+func TestHyperLogLog_Marshal(t *testing.T) {
+	for _, n := range []int{100, 100000} {
+		var h, _ = New(14)
+		for i := 0; i < n; i++ {
+			h.AddString(strconv.Itoa(i))
+		}
+		var data, err = h.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var g = new(HyperLogLog)
+		if err = g.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		if g.Count() != h.Count() {
+			t.Fatalf("n=%d: Count() after unmarshal = %v, want %v", n, g.Count(), h.Count())
+		}
+		if err = g.UnmarshalBinary(data[:len(data)-1]); err != ErrInvalidData {
+			t.Fatalf("UnmarshalBinary() error = %v, want %v", err, ErrInvalidData)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+// Package hashutil bitmap下各概率数据结构共用的哈希工具
+package hashutil
+
+// Mix64 splitmix64的混淆函数，弥补fnv对短输入雪崩效果不足的问题
+func Mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,179 @@
+package roaring
+
+import (
+	"sort"
+)
+
+// arrayContainer 数组容器，有序存放元素，适合稀疏数据
+type arrayContainer struct {
+	content []uint16
+}
+
+var _ container = (*arrayContainer)(nil)
+
+func newArrayContainer(size int) *arrayContainer {
+	return &arrayContainer{content: make([]uint16, 0, size)}
+}
+
+// search 第一个大于等于x的元素下标
+func (ac *arrayContainer) search(x uint16) int {
+	return sort.Search(len(ac.content), func(i int) bool { return ac.content[i] >= x })
+}
+
+func (ac *arrayContainer) add(x uint16) container {
+	var i = ac.search(x)
+	if i < len(ac.content) && ac.content[i] == x {
+		return ac
+	}
+	if len(ac.content) >= arrayMaxSize {
+		// 超出数组容器容量，转换为位图容器
+		return ac.toBitmap().add(x)
+	}
+	ac.content = append(ac.content, 0)
+	copy(ac.content[i+1:], ac.content[i:])
+	ac.content[i] = x
+	return ac
+}
+
+func (ac *arrayContainer) remove(x uint16) container {
+	var i = ac.search(x)
+	if i < len(ac.content) && ac.content[i] == x {
+		ac.content = append(ac.content[:i], ac.content[i+1:]...)
+	}
+	return ac
+}
+
+func (ac *arrayContainer) contains(x uint16) bool {
+	var i = ac.search(x)
+	return i < len(ac.content) && ac.content[i] == x
+}
+
+func (ac *arrayContainer) cardinality() int {
+	return len(ac.content)
+}
+
+func (ac *arrayContainer) rank(x uint16) int {
+	return sort.Search(len(ac.content), func(i int) bool { return ac.content[i] > x })
+}
+
+func (ac *arrayContainer) selectAt(i int) uint16 {
+	return ac.content[i]
+}
+
+func (ac *arrayContainer) each(fn func(uint16) bool) bool {
+	for _, x := range ac.content {
+		if !fn(x) {
+			return false
+		}
+	}
+	return true
+}
+
+func (ac *arrayContainer) numRuns() int {
+	var cnt int
+	for i := range ac.content {
+		if i == 0 || ac.content[i] != ac.content[i-1]+1 {
+			cnt++
+		}
+	}
+	return cnt
+}
+
+func (ac *arrayContainer) clone() container {
+	var c = newArrayContainer(len(ac.content))
+	c.content = append(c.content, ac.content...)
+	return c
+}
+
+func (ac *arrayContainer) toBitmap() *bitmapContainer {
+	var bc = newBitmapContainer()
+	for _, x := range ac.content {
+		bc.words[x>>6] |= 1 << (x & 63)
+	}
+	bc.card = len(ac.content)
+	return bc
+}
+
+// intersect 有序归并求交集
+func (ac *arrayContainer) intersect(other *arrayContainer) container {
+	var (
+		a, b = ac.content, other.content
+		c    = newArrayContainer(0)
+		i, j int
+	)
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			c.content = append(c.content, a[i])
+			i++
+			j++
+		}
+	}
+	return c
+}
+
+// union 有序归并求并集，调用方保证结果不超过arrayMaxSize
+func (ac *arrayContainer) union(other *arrayContainer) container {
+	var (
+		a, b = ac.content, other.content
+		c    = newArrayContainer(len(a) + len(b))
+		i, j int
+	)
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			c.content = append(c.content, a[i])
+			i++
+		case a[i] > b[j]:
+			c.content = append(c.content, b[j])
+			j++
+		default:
+			c.content = append(c.content, a[i])
+			i++
+			j++
+		}
+	}
+	c.content = append(c.content, a[i:]...)
+	c.content = append(c.content, b[j:]...)
+	return c
+}
+
+// symmetricDifference 有序归并求对称差，调用方保证结果不超过arrayMaxSize
+func (ac *arrayContainer) symmetricDifference(other *arrayContainer) container {
+	var (
+		a, b = ac.content, other.content
+		c    = newArrayContainer(len(a) + len(b))
+		i, j int
+	)
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			c.content = append(c.content, a[i])
+			i++
+		case a[i] > b[j]:
+			c.content = append(c.content, b[j])
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	c.content = append(c.content, a[i:]...)
+	c.content = append(c.content, b[j:]...)
+	return c
+}
+
+// filter keep为true时保留在other中的元素，否则保留不在other中的元素
+func (ac *arrayContainer) filter(other container, keep bool) container {
+	var c = newArrayContainer(0)
+	for _, x := range ac.content {
+		if other.contains(x) == keep {
+			c.content = append(c.content, x)
+		}
+	}
+	return c
+}
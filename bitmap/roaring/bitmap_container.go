@@ -0,0 +1,135 @@
+package roaring
+
+import (
+	"math/bits"
+)
+
+// bitmapContainer 位图容器，使用65536位存放元素，适合稠密数据
+type bitmapContainer struct {
+	words []uint64
+	// 基数
+	card int
+}
+
+var _ container = (*bitmapContainer)(nil)
+
+func newBitmapContainer() *bitmapContainer {
+	return &bitmapContainer{words: make([]uint64, bitmapWords)}
+}
+
+func (bc *bitmapContainer) computeCardinality() {
+	bc.card = 0
+	for _, w := range bc.words {
+		bc.card += bits.OnesCount64(w)
+	}
+}
+
+func (bc *bitmapContainer) add(x uint16) container {
+	var mask = uint64(1) << (x & 63)
+	if bc.words[x>>6]&mask == 0 {
+		bc.words[x>>6] |= mask
+		bc.card++
+	}
+	return bc
+}
+
+func (bc *bitmapContainer) remove(x uint16) container {
+	var mask = uint64(1) << (x & 63)
+	if bc.words[x>>6]&mask != 0 {
+		bc.words[x>>6] &^= mask
+		bc.card--
+	}
+	// 基数降到数组容器容量以内时转换为数组容器
+	return normalize(bc)
+}
+
+func (bc *bitmapContainer) contains(x uint16) bool {
+	return bc.words[x>>6]&(1<<(x&63)) != 0
+}
+
+func (bc *bitmapContainer) cardinality() int {
+	return bc.card
+}
+
+func (bc *bitmapContainer) rank(x uint16) int {
+	var cnt int
+	for i := 0; i < int(x>>6); i++ {
+		cnt += bits.OnesCount64(bc.words[i])
+	}
+	// 包含x本身，x&63为63时左移溢出为0，减1后恰好为全1
+	var mask = uint64(1)<<(x&63)<<1 - 1
+	return cnt + bits.OnesCount64(bc.words[x>>6]&mask)
+}
+
+func (bc *bitmapContainer) selectAt(i int) uint16 {
+	for k, w := range bc.words {
+		var cnt = bits.OnesCount64(w)
+		if i >= cnt {
+			i -= cnt
+			continue
+		}
+		// 在当前字中依次去掉最低位的1
+		for ; i > 0; i-- {
+			w &= w - 1
+		}
+		return uint16(k<<6 + bits.TrailingZeros64(w))
+	}
+	return 0
+}
+
+func (bc *bitmapContainer) each(fn func(uint16) bool) bool {
+	for k, w := range bc.words {
+		for w != 0 {
+			if !fn(uint16(k<<6 + bits.TrailingZeros64(w))) {
+				return false
+			}
+			w &= w - 1
+		}
+	}
+	return true
+}
+
+func (bc *bitmapContainer) numRuns() int {
+	var cnt int
+	for i, w := range bc.words {
+		// 每个区间的起点：当前位为1且前一位为0
+		var prev = w << 1
+		if i > 0 {
+			prev |= bc.words[i-1] >> 63
+		}
+		cnt += bits.OnesCount64(w &^ prev)
+	}
+	return cnt
+}
+
+func (bc *bitmapContainer) clone() container {
+	var c = &bitmapContainer{words: make([]uint64, bitmapWords), card: bc.card}
+	copy(c.words, bc.words)
+	return c
+}
+
+func (bc *bitmapContainer) toBitmap() *bitmapContainer {
+	return bc
+}
+
+func (bc *bitmapContainer) toArray() *arrayContainer {
+	var ac = newArrayContainer(bc.card)
+	bc.each(func(x uint16) bool {
+		ac.content = append(ac.content, x)
+		return true
+	})
+	return ac
+}
+
+// setRange 将[start, last]区间内的位置为1，不维护基数
+func (bc *bitmapContainer) setRange(start, last int) {
+	for x := start; x <= last; {
+		if x&63 == 0 && x+63 <= last {
+			bc.words[x>>6] = ^uint64(0)
+			x += 64
+			continue
+		}
+		bc.words[x>>6] |= 1 << (uint(x) & 63)
+		x++
+	}
+}
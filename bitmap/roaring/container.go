@@ -0,0 +1,119 @@
+package roaring
+
+const (
+	// arrayMaxSize 数组容器的最大基数，超过后转换为位图容器
+	arrayMaxSize = 4096
+	// bitmapWords 位图容器的字数（65536位）
+	bitmapWords = 1024
+)
+
+// container 存放高16位相同的元素的低16位
+type container interface {
+	// add 添加元素，返回添加后的容器（可能发生类型转换）
+	add(x uint16) container
+	// remove 删除元素，返回删除后的容器（可能发生类型转换）
+	remove(x uint16) container
+	contains(x uint16) bool
+	cardinality() int
+	// rank 小于等于x的元素个数
+	rank(x uint16) int
+	// selectAt 第i小的元素（从0开始）
+	selectAt(i int) uint16
+	// each 按升序遍历，fn返回false时停止遍历并返回false
+	each(fn func(uint16) bool) bool
+	// numRuns 连续区间个数，用于选择最省空间的容器
+	numRuns() int
+	clone() container
+	// toBitmap 转换为位图容器，可能返回自身，调用方不应修改返回值
+	toBitmap() *bitmapContainer
+}
+
+// optimize 根据序列化大小选择最省空间的容器类型
+func optimize(c container) container {
+	var (
+		card    = c.cardinality()
+		runs    = c.numRuns()
+		runSize = 2 + 4*runs
+		nonRun  = 2 * card
+	)
+	if card > arrayMaxSize {
+		nonRun = 8 * bitmapWords
+	}
+	if runSize < nonRun {
+		if rc, ok := c.(*runContainer); ok {
+			return rc
+		}
+		return newRunContainerFrom(c)
+	}
+	if _, ok := c.(*runContainer); !ok {
+		return c
+	}
+	return normalize(c.toBitmap().clone().(*bitmapContainer))
+}
+
+// normalize 基数不超过arrayMaxSize的位图容器转换为数组容器
+func normalize(bc *bitmapContainer) container {
+	if bc.card <= arrayMaxSize {
+		return bc.toArray()
+	}
+	return bc
+}
+
+// and 两个容器求交集
+func and(a, b container) container {
+	var (
+		aa, aok = a.(*arrayContainer)
+		ba, bok = b.(*arrayContainer)
+	)
+	switch {
+	case aok && bok:
+		return aa.intersect(ba)
+	case aok:
+		return aa.filter(b, true)
+	case bok:
+		return ba.filter(a, true)
+	}
+	return wordsOp(a.toBitmap(), b.toBitmap(), func(x, y uint64) uint64 { return x & y })
+}
+
+// or 两个容器求并集
+func or(a, b container) container {
+	var (
+		aa, aok = a.(*arrayContainer)
+		ba, bok = b.(*arrayContainer)
+	)
+	if aok && bok && len(aa.content)+len(ba.content) <= arrayMaxSize {
+		return aa.union(ba)
+	}
+	return wordsOp(a.toBitmap(), b.toBitmap(), func(x, y uint64) uint64 { return x | y })
+}
+
+// xor 两个容器求对称差
+func xor(a, b container) container {
+	var (
+		aa, aok = a.(*arrayContainer)
+		ba, bok = b.(*arrayContainer)
+	)
+	if aok && bok && len(aa.content)+len(ba.content) <= arrayMaxSize {
+		return aa.symmetricDifference(ba)
+	}
+	return wordsOp(a.toBitmap(), b.toBitmap(), func(x, y uint64) uint64 { return x ^ y })
+}
+
+// andNot 两个容器求差集
+func andNot(a, b container) container {
+	if aa, ok := a.(*arrayContainer); ok {
+		return aa.filter(b, false)
+	}
+	return wordsOp(a.toBitmap(), b.toBitmap(), func(x, y uint64) uint64 { return x &^ y })
+}
+
+// wordsOp 按字对两个位图容器做位运算，返回新的容器
+func wordsOp(a, b *bitmapContainer, op func(x, y uint64) uint64) container {
+	var c = newBitmapContainer()
+	for i := 0; i < bitmapWords; i++ {
+		c.words[i] = op(a.words[i], b.words[i])
+	}
+	c.computeCardinality()
+	return normalize(c)
+}
@@ -0,0 +1,12 @@
+package roaring
+
+import (
+	"errors"
+)
+
+var (
+	// ErrInvalidCookie 序列化数据的cookie不合法
+	ErrInvalidCookie = errors.New("roaring: invalid cookie")
+	// ErrInvalidData 序列化数据不合法
+	ErrInvalidData = errors.New("roaring: invalid data")
+)
@@ -0,0 +1,400 @@
+package roaring
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Bitmap Roaring压缩位图
+// 将uint32按高16位分桶，每个桶根据数据分布使用数组、位图或行程容器存放低16位
+type Bitmap struct {
+	// 有序的高16位
+	keys []uint16
+	// 与keys一一对应的容器
+	containers []container
+}
+
+// New 创建一个空的Roaring位图
+func New() *Bitmap {
+	return &Bitmap{}
+}
+
+// BitmapOf 创建包含values的Roaring位图
+func BitmapOf(values ...uint32) *Bitmap {
+	var b = New()
+	b.AddMany(values)
+	return b
+}
+
+func highbits(x uint32) uint16 {
+	return uint16(x >> 16)
+}
+
+func lowbits(x uint32) uint16 {
+	return uint16(x)
+}
+
+// search 第一个大于等于key的下标
+func (b *Bitmap) search(key uint16) int {
+	return sort.Search(len(b.keys), func(i int) bool { return b.keys[i] >= key })
+}
+
+// find key对应的容器下标
+func (b *Bitmap) find(key uint16) (int, bool) {
+	var i = b.search(key)
+	return i, i < len(b.keys) && b.keys[i] == key
+}
+
+// insertAt 在下标i处插入容器
+func (b *Bitmap) insertAt(i int, key uint16, c container) {
+	b.keys = append(b.keys, 0)
+	copy(b.keys[i+1:], b.keys[i:])
+	b.keys[i] = key
+	b.containers = append(b.containers, nil)
+	copy(b.containers[i+1:], b.containers[i:])
+	b.containers[i] = c
+}
+
+// removeAt 删除下标i处的容器
+func (b *Bitmap) removeAt(i int) {
+	b.keys = append(b.keys[:i], b.keys[i+1:]...)
+	copy(b.containers[i:], b.containers[i+1:])
+	b.containers[len(b.containers)-1] = nil
+	b.containers = b.containers[:len(b.containers)-1]
+}
+
+// Add 添加元素x
+func (b *Bitmap) Add(x uint32) {
+	var i, has = b.find(highbits(x))
+	if !has {
+		b.insertAt(i, highbits(x), newArrayContainer(1))
+	}
+	b.containers[i] = b.containers[i].add(lowbits(x))
+}
+
+// AddMany 批量添加元素
+func (b *Bitmap) AddMany(values []uint32) {
+	for _, x := range values {
+		b.Add(x)
+	}
+}
+
+// AddRange 添加[start, end)区间内的所有元素
+func (b *Bitmap) AddRange(start, end uint64) {
+	if end > 1<<32 {
+		end = 1 << 32
+	}
+	for start < end {
+		var (
+			key  = uint16(start >> 16)
+			last = end - 1
+		)
+		if last>>16 != start>>16 {
+			last = start | 0xffff
+		}
+		var rc = &runContainer{runs: []interval{{start: uint16(start), last: uint16(last)}}}
+		if i, has := b.find(key); has {
+			b.containers[i] = optimize(or(b.containers[i], rc))
+		} else {
+			b.insertAt(i, key, optimize(rc))
+		}
+		start = last + 1
+	}
+}
+
+// Remove 删除元素x
+func (b *Bitmap) Remove(x uint32) {
+	var i, has = b.find(highbits(x))
+	if !has {
+		return
+	}
+	b.containers[i] = b.containers[i].remove(lowbits(x))
+	if b.containers[i].cardinality() == 0 {
+		b.removeAt(i)
+	}
+}
+
+// Contains 是否包含元素x
+func (b *Bitmap) Contains(x uint32) bool {
+	var i, has = b.find(highbits(x))
+	return has && b.containers[i].contains(lowbits(x))
+}
+
+// Cardinality 元素个数
+func (b *Bitmap) Cardinality() uint64 {
+	var card uint64
+	for _, c := range b.containers {
+		card += uint64(c.cardinality())
+	}
+	return card
+}
+
+// IsEmpty 是否为空
+func (b *Bitmap) IsEmpty() bool {
+	return len(b.keys) == 0
+}
+
+// Clear 清空
+func (b *Bitmap) Clear() {
+	b.keys, b.containers = nil, nil
+}
+
+// Minimum 最小元素，位图为空时返回false
+func (b *Bitmap) Minimum() (uint32, bool) {
+	if b.IsEmpty() {
+		return 0, false
+	}
+	return uint32(b.keys[0])<<16 | uint32(b.containers[0].selectAt(0)), true
+}
+
+// Maximum 最大元素，位图为空时返回false
+func (b *Bitmap) Maximum() (uint32, bool) {
+	if b.IsEmpty() {
+		return 0, false
+	}
+	var (
+		n = len(b.keys) - 1
+		c = b.containers[n]
+	)
+	return uint32(b.keys[n])<<16 | uint32(c.selectAt(c.cardinality()-1)), true
+}
+
+// Rank 小于等于x的元素个数
+func (b *Bitmap) Rank(x uint32) uint64 {
+	var rank uint64
+	for i, key := range b.keys {
+		if key > highbits(x) {
+			break
+		}
+		if key < highbits(x) {
+			rank += uint64(b.containers[i].cardinality())
+			continue
+		}
+		rank += uint64(b.containers[i].rank(lowbits(x)))
+	}
+	return rank
+}
+
+// Select 第i小的元素（从0开始），i超出基数时返回false
+func (b *Bitmap) Select(i uint64) (uint32, bool) {
+	for k, c := range b.containers {
+		var card = uint64(c.cardinality())
+		if i < card {
+			return uint32(b.keys[k])<<16 | uint32(c.selectAt(int(i))), true
+		}
+		i -= card
+	}
+	return 0, false
+}
+
+// Each 按升序遍历所有元素，fn返回false时停止遍历
+func (b *Bitmap) Each(fn func(x uint32) bool) {
+	for i, c := range b.containers {
+		var high = uint32(b.keys[i]) << 16
+		if !c.each(func(x uint16) bool { return fn(high | uint32(x)) }) {
+			return
+		}
+	}
+}
+
+// ToArray 按升序返回所有元素
+func (b *Bitmap) ToArray() []uint32 {
+	var values = make([]uint32, 0, b.Cardinality())
+	b.Each(func(x uint32) bool {
+		values = append(values, x)
+		return true
+	})
+	return values
+}
+
+// Iterator 创建一个升序迭代器
+func (b *Bitmap) Iterator() *Iterator {
+	return &Iterator{b: b}
+}
+
+// Iterator Roaring位图迭代器，迭代期间不应修改位图
+type Iterator struct {
+	b *Bitmap
+	// 下一个要展开的容器下标
+	ci int
+	// 当前容器的高16位
+	high uint32
+	// 当前容器展开后的低16位
+	buf []uint16
+	// buf中下一个元素的下标
+	pos int
+}
+
+// HasNext 是否还有元素
+func (it *Iterator) HasNext() bool {
+	for it.pos >= len(it.buf) {
+		if it.ci >= len(it.b.containers) {
+			return false
+		}
+		// 逐个容器展开，避免在位图或行程容器上反复selectAt
+		var c = it.b.containers[it.ci]
+		it.high = uint32(it.b.keys[it.ci]) << 16
+		it.buf, it.pos = it.buf[:0], 0
+		c.each(func(x uint16) bool {
+			it.buf = append(it.buf, x)
+			return true
+		})
+		it.ci++
+	}
+	return true
+}
+
+// Next 下一个元素，调用前需要确认HasNext为true
+func (it *Iterator) Next() uint32 {
+	if !it.HasNext() {
+		return 0
+	}
+	var x = it.buf[it.pos]
+	it.pos++
+	return it.high | uint32(x)
+}
+
+// Clone 深拷贝
+func (b *Bitmap) Clone() *Bitmap {
+	var c = &Bitmap{keys: make([]uint16, len(b.keys)), containers: make([]container, len(b.containers))}
+	copy(c.keys, b.keys)
+	for i := range b.containers {
+		c.containers[i] = b.containers[i].clone()
+	}
+	return c
+}
+
+// Equal 两个位图是否包含相同的元素
+func (b *Bitmap) Equal(other *Bitmap) bool {
+	if len(b.keys) != len(other.keys) {
+		return false
+	}
+	for i := range b.keys {
+		if b.keys[i] != other.keys[i] || b.containers[i].cardinality() != other.containers[i].cardinality() {
+			return false
+		}
+		var (
+			oc    = other.containers[i]
+			equal = b.containers[i].each(func(x uint16) bool { return oc.contains(x) })
+		)
+		if !equal {
+			return false
+		}
+	}
+	return true
+}
+
+// merge 按key归并两个位图，op为两边都存在的容器的运算，keepLeft、keepRight表示只存在于一边的容器是否保留
+func (b *Bitmap) merge(other *Bitmap, op func(a, b container) container, keepLeft, keepRight bool) {
+	var (
+		keys       = make([]uint16, 0, len(b.keys)+len(other.keys))
+		containers = make([]container, 0, len(b.keys)+len(other.keys))
+		i, j       int
+	)
+	var appendContainer = func(key uint16, c container) {
+		if c.cardinality() > 0 {
+			keys = append(keys, key)
+			containers = append(containers, c)
+		}
+	}
+	for i < len(b.keys) && j < len(other.keys) {
+		switch {
+		case b.keys[i] < other.keys[j]:
+			if keepLeft {
+				appendContainer(b.keys[i], b.containers[i])
+			}
+			i++
+		case b.keys[i] > other.keys[j]:
+			if keepRight {
+				appendContainer(other.keys[j], other.containers[j].clone())
+			}
+			j++
+		default:
+			appendContainer(b.keys[i], op(b.containers[i], other.containers[j]))
+			i++
+			j++
+		}
+	}
+	for ; keepLeft && i < len(b.keys); i++ {
+		appendContainer(b.keys[i], b.containers[i])
+	}
+	for ; keepRight && j < len(other.keys); j++ {
+		appendContainer(other.keys[j], other.containers[j].clone())
+	}
+	b.keys, b.containers = keys, containers
+}
+
+// And 原地求交集
+func (b *Bitmap) And(other *Bitmap) {
+	b.merge(other, and, false, false)
+}
+
+// Or 原地求并集
+func (b *Bitmap) Or(other *Bitmap) {
+	b.merge(other, or, true, true)
+}
+
+// Xor 原地求对称差
+func (b *Bitmap) Xor(other *Bitmap) {
+	b.merge(other, xor, true, true)
+}
+
+// AndNot 原地求差集（b中存在而other中不存在）
+func (b *Bitmap) AndNot(other *Bitmap) {
+	b.merge(other, andNot, true, false)
+}
+
+// And 求交集，返回新的位图
+func And(a, b *Bitmap) *Bitmap {
+	var c = a.Clone()
+	c.And(b)
+	return c
+}
+
+// Or 求并集，返回新的位图
+func Or(a, b *Bitmap) *Bitmap {
+	var c = a.Clone()
+	c.Or(b)
+	return c
+}
+
+// Xor 求对称差，返回新的位图
+func Xor(a, b *Bitmap) *Bitmap {
+	var c = a.Clone()
+	c.Xor(b)
+	return c
+}
+
+// AndNot 求差集，返回新的位图
+func AndNot(a, b *Bitmap) *Bitmap {
+	var c = a.Clone()
+	c.AndNot(b)
+	return c
+}
+
+// RunOptimize 将每个容器转换为序列化后最省空间的类型，对连续数据效果明显
+func (b *Bitmap) RunOptimize() {
+	for i := range b.containers {
+		b.containers[i] = optimize(b.containers[i])
+	}
+}
+
+// String 以{1,3,5}的形式输出
+func (b *Bitmap) String() string {
+	var (
+		sb    strings.Builder
+		first = true
+	)
+	sb.WriteByte('{')
+	b.Each(func(x uint32) bool {
+		if !first {
+			sb.WriteByte(',')
+		}
+		first = false
+		fmt.Fprintf(&sb, "%d", x)
+		return true
+	})
+	sb.WriteByte('}')
+	return sb.String()
+}
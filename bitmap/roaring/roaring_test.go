@@ -0,0 +1,240 @@
+package roaring
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// randomSet 生成覆盖三种容器的测试数据
+func randomSet(r *rand.Rand) map[uint32]bool {
+	var set = make(map[uint32]bool)
+	// 稀疏：数组容器
+	for i := 0; i < 1000; i++ {
+		set[r.Uint32()%(1<<20)] = true
+	}
+	// 稠密：位图容器
+	for i := 0; i < 20000; i++ {
+		set[5<<16|r.Uint32()%(1<<16)] = true
+	}
+	// 连续：行程容器
+	var start = 9<<16 + r.Uint32()%1000
+	for i := uint32(0); i < 3000; i++ {
+		set[start+i] = true
+	}
+	return set
+}
+
+func fromSet(set map[uint32]bool) *Bitmap {
+	var b = New()
+	for x := range set {
+		b.Add(x)
+	}
+	b.RunOptimize()
+	return b
+}
+
+func sortedKeys(set map[uint32]bool) []uint32 {
+	var values = make([]uint32, 0, len(set))
+	for x := range set {
+		values = append(values, x)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	return values
+}
+
+func TestBitmap_Basic(t *testing.T) {
+	var (
+		r    = rand.New(rand.NewSource(1))
+		set  = randomSet(r)
+		b    = fromSet(set)
+		want = sortedKeys(set)
+	)
+	if b.Cardinality() != uint64(len(set)) {
+		t.Fatalf("Cardinality() = %v, want %v", b.Cardinality(), len(set))
+	}
+	if got := b.ToArray(); !reflect.DeepEqual(got, want) {
+		t.Fatal("ToArray() differs from the inserted values")
+	}
+	var i int
+	for it := b.Iterator(); it.HasNext(); i++ {
+		if x := it.Next(); x != want[i] {
+			t.Fatalf("Iterator.Next() = %v, want %v", x, want[i])
+		}
+	}
+	for _, k := range []int{0, 1, 500, len(want) - 1} {
+		if x, ok := b.Select(uint64(k)); !ok || x != want[k] {
+			t.Fatalf("Select(%d) = %v, want %v", k, x, want[k])
+		}
+		if rank := b.Rank(want[k]); rank != uint64(k+1) {
+			t.Fatalf("Rank(%d) = %v, want %v", want[k], rank, k+1)
+		}
+	}
+	for _, x := range want[:2000] {
+		b.Remove(x)
+	}
+	if b.Contains(want[0]) || !b.Contains(want[2000]) {
+		t.Fatal("Remove() removed the wrong values")
+	}
+}
+
+func TestBitmap_SetOperations(t *testing.T) {
+	var (
+		r      = rand.New(rand.NewSource(2))
+		sa, sb = randomSet(r), randomSet(r)
+		a, b   = fromSet(sa), fromSet(sb)
+	)
+	var expect = func(pred func(x uint32) bool) []uint32 {
+		var set = make(map[uint32]bool)
+		for x := range sa {
+			if pred(x) {
+				set[x] = true
+			}
+		}
+		for x := range sb {
+			if pred(x) {
+				set[x] = true
+			}
+		}
+		return sortedKeys(set)
+	}
+	tests := []struct {
+		name string
+		got  *Bitmap
+		want []uint32
+	}{
+		{name: "and", got: And(a, b), want: expect(func(x uint32) bool { return sa[x] && sb[x] })},
+		{name: "or", got: Or(a, b), want: expect(func(x uint32) bool { return sa[x] || sb[x] })},
+		{name: "xor", got: Xor(a, b), want: expect(func(x uint32) bool { return sa[x] != sb[x] })},
+		{name: "andNot", got: AndNot(a, b), want: expect(func(x uint32) bool { return sa[x] && !sb[x] })},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got.ToArray(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s: got %d values, want %d", tt.name, len(got), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestBitmap_Serialization(t *testing.T) {
+	// 与规范一致的最小示例：cookie、容器数、描述头、偏移量头、数组容器
+	var want = []byte{
+		0x3a, 0x30, 0, 0, 1, 0, 0, 0,
+		0, 0, 2, 0,
+		16, 0, 0, 0,
+		1, 0, 2, 0, 3, 0,
+	}
+	if got, _ := BitmapOf(1, 2, 3).MarshalBinary(); !bytes.Equal(got, want) {
+		t.Fatalf("MarshalBinary() = %v, want %v", got, want)
+	}
+
+	var b = fromSet(randomSet(rand.New(rand.NewSource(3))))
+	b.AddRange(100<<16, 100<<16+70000)
+	b.RunOptimize()
+	var data, err = b.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != b.SerializedSizeInBytes() {
+		t.Fatalf("len(data) = %v, want %v", len(data), b.SerializedSizeInBytes())
+	}
+	var c = New()
+	if err = c.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !c.Equal(b) {
+		t.Fatal("unmarshaled bitmap differs from the original")
+	}
+	if err = c.UnmarshalBinary([]byte{1, 2, 3, 4}); err != ErrInvalidCookie {
+		t.Fatalf("UnmarshalBinary() error = %v, want %v", err, ErrInvalidCookie)
+	}
+}
+
+func TestBitmap_ReferenceFormat(t *testing.T) {
+	// testdata下的文件由RoaringBitmap的参考实现生成，内容为
+	// {k | k < 100000且k%1000 == 0} ∪ {3k | 100000 <= k < 200000} ∪ [700000, 800000)
+	// bitmapwithruns.bin在生成前调用过runOptimize，同时包含数组、位图与行程容器
+	var want = New()
+	for k := uint32(0); k < 100000; k += 1000 {
+		want.Add(k)
+	}
+	for k := uint32(100000); k < 200000; k++ {
+		want.Add(3 * k)
+	}
+	want.AddRange(700000, 800000)
+	for _, name := range []string{"bitmapwithoutruns.bin", "bitmapwithruns.bin"} {
+		t.Run(name, func(t *testing.T) {
+			var data, err = ioutil.ReadFile(filepath.Join("testdata", name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var b = New()
+			if n, err := b.ReadFrom(bytes.NewReader(data)); err != nil || n != int64(len(data)) {
+				t.Fatalf("ReadFrom() = %v, %v, want %v, nil", n, err, len(data))
+			}
+			if !b.Equal(want) || b.Cardinality() != want.Cardinality() {
+				t.Fatalf("ReadFrom() got %v values, want %v", b.Cardinality(), want.Cardinality())
+			}
+			if r := b.Rank(3 * 150000); r != 100+50001 {
+				t.Fatalf("Rank() = %v, want %v", r, 100+50001)
+			}
+			if x, ok := b.Select(100 + 100000); !ok || x != 700000 {
+				t.Fatalf("Select() = %v, %v, want 700000", x, ok)
+			}
+		})
+	}
+}
+
+func TestBitmap_ReadFromInvalid(t *testing.T) {
+	// 无行程容器格式：cookie、容器数、描述头（key、基数-1）、偏移量头，之后为容器内容
+	var encode = func(card uint16, content ...uint16) []byte {
+		var data = []byte{0x3a, 0x30, 0, 0, 1, 0, 0, 0, 0, 0, byte(card - 1), byte((card - 1) >> 8), 16, 0, 0, 0}
+		for _, x := range content {
+			data = append(data, byte(x), byte(x>>8))
+		}
+		return data
+	}
+	// 含行程容器格式：cookie（低16位12347，高16位为容器数-1）、行程标记、描述头，之后为行程个数与每个行程的起点、长度-1
+	var encodeRuns = func(card uint16, runs ...uint16) []byte {
+		var data = []byte{0x3b, 0x30, 0, 0, 1, 0, 0, byte(card - 1), byte((card - 1) >> 8), byte(len(runs) / 2), 0}
+		for _, x := range runs {
+			data = append(data, byte(x), byte(x>>8))
+		}
+		return data
+	}
+	var bitmapOf = func(card uint16, values ...uint16) []byte {
+		var words = make([]byte, 8*bitmapWords)
+		for _, x := range values {
+			words[x/8] |= 1 << (x % 8)
+		}
+		return append(encode(card), words...)
+	}
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "unsorted array", data: encode(3, 1, 3, 2)},
+		{name: "duplicate array", data: encode(3, 1, 2, 2)},
+		{name: "overlapping runs", data: encodeRuns(8, 10, 4, 12, 2)},
+		{name: "unsorted runs", data: encodeRuns(8, 20, 3, 10, 3)},
+		{name: "run cardinality", data: encodeRuns(9, 10, 3, 20, 3)},
+		{name: "small bitmap", data: bitmapOf(4097, 1, 2, 3)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := New().UnmarshalBinary(tt.data); err != ErrInvalidData {
+				t.Fatalf("UnmarshalBinary() error = %v, want %v", err, ErrInvalidData)
+			}
+		})
+	}
+	// 构造正确的输入可以正常读取
+	var b = New()
+	if err := b.UnmarshalBinary(encodeRuns(8, 10, 3, 20, 3)); err != nil || b.Cardinality() != 8 || !b.Contains(23) {
+		t.Fatalf("UnmarshalBinary() = %v, got %v", err, b)
+	}
+}
@@ -0,0 +1,154 @@
+package roaring
+
+import (
+	"sort"
+)
+
+// interval 闭区间[start, last]
+type interval struct {
+	start uint16
+	last  uint16
+}
+
+// runContainer 行程容器，以有序不相交的区间存放元素，适合连续数据
+type runContainer struct {
+	runs []interval
+}
+
+var _ container = (*runContainer)(nil)
+
+// newRunContainerFrom 将其他容器转换为行程容器
+func newRunContainerFrom(c container) *runContainer {
+	var rc = &runContainer{runs: make([]interval, 0, c.numRuns())}
+	c.each(func(x uint16) bool {
+		if n := len(rc.runs); n > 0 && rc.runs[n-1].last+1 == x {
+			rc.runs[n-1].last = x
+		} else {
+			rc.runs = append(rc.runs, interval{start: x, last: x})
+		}
+		return true
+	})
+	return rc
+}
+
+// search 最后一个start小于等于x的区间下标，不存在时返回-1
+func (rc *runContainer) search(x uint16) int {
+	return sort.Search(len(rc.runs), func(i int) bool { return rc.runs[i].start > x }) - 1
+}
+
+func (rc *runContainer) add(x uint16) container {
+	var i = rc.search(x)
+	if i >= 0 && x <= rc.runs[i].last {
+		return rc
+	}
+	var (
+		mergeLeft  = i >= 0 && int(rc.runs[i].last)+1 == int(x)
+		mergeRight = i+1 < len(rc.runs) && int(x)+1 == int(rc.runs[i+1].start)
+	)
+	switch {
+	case mergeLeft && mergeRight:
+		// x恰好填补两个区间之间的空隙
+		rc.runs[i].last = rc.runs[i+1].last
+		rc.runs = append(rc.runs[:i+1], rc.runs[i+2:]...)
+	case mergeLeft:
+		rc.runs[i].last = x
+	case mergeRight:
+		rc.runs[i+1].start = x
+	default:
+		rc.runs = append(rc.runs, interval{})
+		copy(rc.runs[i+2:], rc.runs[i+1:])
+		rc.runs[i+1] = interval{start: x, last: x}
+	}
+	return rc
+}
+
+func (rc *runContainer) remove(x uint16) container {
+	var i = rc.search(x)
+	if i < 0 || x > rc.runs[i].last {
+		return rc
+	}
+	var r = rc.runs[i]
+	switch {
+	case r.start == r.last:
+		rc.runs = append(rc.runs[:i], rc.runs[i+1:]...)
+	case x == r.start:
+		rc.runs[i].start++
+	case x == r.last:
+		rc.runs[i].last--
+	default:
+		// 从中间删除时区间一分为二
+		rc.runs = append(rc.runs, interval{})
+		copy(rc.runs[i+2:], rc.runs[i+1:])
+		rc.runs[i].last = x - 1
+		rc.runs[i+1] = interval{start: x + 1, last: r.last}
+	}
+	return rc
+}
+
+func (rc *runContainer) contains(x uint16) bool {
+	var i = rc.search(x)
+	return i >= 0 && x <= rc.runs[i].last
+}
+
+func (rc *runContainer) cardinality() int {
+	var card int
+	for _, r := range rc.runs {
+		card += int(r.last-r.start) + 1
+	}
+	return card
+}
+
+func (rc *runContainer) rank(x uint16) int {
+	var cnt int
+	for _, r := range rc.runs {
+		if x < r.start {
+			break
+		}
+		if x <= r.last {
+			return cnt + int(x-r.start) + 1
+		}
+		cnt += int(r.last-r.start) + 1
+	}
+	return cnt
+}
+
+func (rc *runContainer) selectAt(i int) uint16 {
+	for _, r := range rc.runs {
+		var n = int(r.last-r.start) + 1
+		if i < n {
+			return r.start + uint16(i)
+		}
+		i -= n
+	}
+	return 0
+}
+
+func (rc *runContainer) each(fn func(uint16) bool) bool {
+	for _, r := range rc.runs {
+		for x := int(r.start); x <= int(r.last); x++ {
+			if !fn(uint16(x)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (rc *runContainer) numRuns() int {
+	return len(rc.runs)
+}
+
+func (rc *runContainer) clone() container {
+	var c = &runContainer{runs: make([]interval, len(rc.runs))}
+	copy(c.runs, rc.runs)
+	return c
+}
+
+func (rc *runContainer) toBitmap() *bitmapContainer {
+	var bc = newBitmapContainer()
+	for _, r := range rc.runs {
+		bc.setRange(int(r.start), int(r.last))
+	}
+	bc.computeCardinality()
+	return bc
+}
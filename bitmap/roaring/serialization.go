@@ -0,0 +1,310 @@
+package roaring
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+)
+
+// 可移植序列化格式，参考 https://github.com/RoaringBitmap/RoaringFormatSpec
+const (
+	// serialCookieNoRunContainer 不包含行程容器时的cookie
+	serialCookieNoRunContainer = 12346
+	// serialCookie 包含行程容器时的cookie（低16位）
+	serialCookie = 12347
+	// noOffsetThreshold 包含行程容器且容器数小于该值时不写入偏移量头
+	noOffsetThreshold = 4
+)
+
+// hasRunContainer 是否包含行程容器
+func (b *Bitmap) hasRunContainer() bool {
+	for _, c := range b.containers {
+		if _, ok := c.(*runContainer); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// containerSize 容器序列化后的字节数
+func containerSize(c container) int {
+	if rc, ok := c.(*runContainer); ok {
+		return 2 + 4*len(rc.runs)
+	}
+	if c.cardinality() <= arrayMaxSize {
+		return 2 * c.cardinality()
+	}
+	return 8 * bitmapWords
+}
+
+// headerSize 头部（cookie、描述头与偏移量头）的字节数
+func (b *Bitmap) headerSize() int {
+	var n = len(b.keys)
+	if b.hasRunContainer() {
+		var size = 4 + (n+7)/8 + 4*n
+		if n >= noOffsetThreshold {
+			size += 4 * n
+		}
+		return size
+	}
+	return 4 + 4 + 8*n
+}
+
+// SerializedSizeInBytes 序列化后的字节数
+func (b *Bitmap) SerializedSizeInBytes() int {
+	var size = b.headerSize()
+	for _, c := range b.containers {
+		size += containerSize(c)
+	}
+	return size
+}
+
+// WriteTo 按可移植格式序列化到w，可与其他语言的Roaring实现交换数据
+func (b *Bitmap) WriteTo(w io.Writer) (int64, error) {
+	var (
+		n      = len(b.keys)
+		hasRun = b.hasRunContainer()
+		buf    = make([]byte, 0, b.SerializedSizeInBytes())
+		le     = binary.LittleEndian
+		tmp    [8]byte
+	)
+	var putUint16 = func(v uint16) {
+		le.PutUint16(tmp[:2], v)
+		buf = append(buf, tmp[:2]...)
+	}
+	var putUint32 = func(v uint32) {
+		le.PutUint32(tmp[:4], v)
+		buf = append(buf, tmp[:4]...)
+	}
+	if hasRun {
+		putUint32(serialCookie | uint32(n-1)<<16)
+		// 行程容器位集
+		var runFlags = make([]byte, (n+7)/8)
+		for i, c := range b.containers {
+			if _, ok := c.(*runContainer); ok {
+				runFlags[i/8] |= 1 << (uint(i) % 8)
+			}
+		}
+		buf = append(buf, runFlags...)
+	} else {
+		putUint32(serialCookieNoRunContainer)
+		putUint32(uint32(n))
+	}
+	// 描述头：key与基数-1
+	for i, c := range b.containers {
+		putUint16(b.keys[i])
+		putUint16(uint16(c.cardinality() - 1))
+	}
+	// 偏移量头
+	if !hasRun || n >= noOffsetThreshold {
+		var offset = b.headerSize()
+		for _, c := range b.containers {
+			putUint32(uint32(offset))
+			offset += containerSize(c)
+		}
+	}
+	for _, c := range b.containers {
+		switch {
+		case isRun(c):
+			var rc = c.(*runContainer)
+			putUint16(uint16(len(rc.runs)))
+			for _, r := range rc.runs {
+				putUint16(r.start)
+				putUint16(r.last - r.start)
+			}
+		case c.cardinality() <= arrayMaxSize:
+			c.each(func(x uint16) bool {
+				putUint16(x)
+				return true
+			})
+		default:
+			for _, word := range c.toBitmap().words {
+				le.PutUint64(tmp[:], word)
+				buf = append(buf, tmp[:]...)
+			}
+		}
+	}
+	var nw, err = w.Write(buf)
+	return int64(nw), err
+}
+
+func isRun(c container) bool {
+	var _, ok = c.(*runContainer)
+	return ok
+}
+
+// countingReader 记录已读取的字节数
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	var n, err = cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
+
+// ReadFrom 从r中按可移植格式反序列化，覆盖当前内容
+func (b *Bitmap) ReadFrom(r io.Reader) (int64, error) {
+	var (
+		cr  = &countingReader{r: r}
+		le  = binary.LittleEndian
+		tmp [8]byte
+	)
+	var readUint16 = func() (uint16, error) {
+		if _, err := io.ReadFull(cr, tmp[:2]); err != nil {
+			return 0, err
+		}
+		return le.Uint16(tmp[:2]), nil
+	}
+	var readUint32 = func() (uint32, error) {
+		if _, err := io.ReadFull(cr, tmp[:4]); err != nil {
+			return 0, err
+		}
+		return le.Uint32(tmp[:4]), nil
+	}
+	var cookie, err = readUint32()
+	if err != nil {
+		return cr.n, err
+	}
+	var (
+		n        int
+		runFlags []byte
+		hasRun   = cookie&0xffff == serialCookie
+	)
+	switch {
+	case hasRun:
+		n = int(cookie>>16) + 1
+		runFlags = make([]byte, (n+7)/8)
+		if _, err = io.ReadFull(cr, runFlags); err != nil {
+			return cr.n, err
+		}
+	case cookie == serialCookieNoRunContainer:
+		var size uint32
+		if size, err = readUint32(); err != nil {
+			return cr.n, err
+		}
+		if size > 1<<16 {
+			return cr.n, ErrInvalidData
+		}
+		n = int(size)
+	default:
+		return cr.n, ErrInvalidCookie
+	}
+	var (
+		keys  = make([]uint16, n)
+		cards = make([]int, n)
+	)
+	for i := 0; i < n; i++ {
+		var card uint16
+		if keys[i], err = readUint16(); err != nil {
+			return cr.n, err
+		}
+		if card, err = readUint16(); err != nil {
+			return cr.n, err
+		}
+		cards[i] = int(card) + 1
+		if i > 0 && keys[i] <= keys[i-1] {
+			return cr.n, ErrInvalidData
+		}
+	}
+	if !hasRun || n >= noOffsetThreshold {
+		// 顺序读取时不需要偏移量，直接跳过
+		if _, err = io.CopyN(ioutil.Discard, cr, int64(4*n)); err != nil {
+			return cr.n, err
+		}
+	}
+	var containers = make([]container, n)
+	for i := 0; i < n; i++ {
+		switch {
+		case hasRun && runFlags[i/8]&(1<<(uint(i)%8)) != 0:
+			var numRuns uint16
+			if numRuns, err = readUint16(); err != nil {
+				return cr.n, err
+			}
+			var rc = &runContainer{runs: make([]interval, numRuns)}
+			for k := range rc.runs {
+				var start, length uint16
+				if start, err = readUint16(); err != nil {
+					return cr.n, err
+				}
+				if length, err = readUint16(); err != nil {
+					return cr.n, err
+				}
+				if int(start)+int(length) > 0xffff {
+					return cr.n, ErrInvalidData
+				}
+				rc.runs[k] = interval{start: start, last: start + length}
+			}
+			containers[i] = rc
+		case cards[i] <= arrayMaxSize:
+			var ac = &arrayContainer{content: make([]uint16, cards[i])}
+			for k := range ac.content {
+				if ac.content[k], err = readUint16(); err != nil {
+					return cr.n, err
+				}
+			}
+			containers[i] = ac
+		default:
+			var (
+				bc  = newBitmapContainer()
+				raw = make([]byte, 8*bitmapWords)
+			)
+			if _, err = io.ReadFull(cr, raw); err != nil {
+				return cr.n, err
+			}
+			for k := range bc.words {
+				bc.words[k] = le.Uint64(raw[8*k:])
+			}
+			bc.computeCardinality()
+			containers[i] = bc
+		}
+		if !validContainer(containers[i], cards[i]) {
+			return cr.n, ErrInvalidData
+		}
+	}
+	b.keys, b.containers = keys, containers
+	return cr.n, nil
+}
+
+// validContainer 反序列化得到的容器是否合法：内容有序、不重叠，且基数与描述头中的card一致
+// 查找、rank、select与集合运算都依赖这些性质，不合法的容器会导致错误的结果
+func validContainer(c container, card int) bool {
+	switch c := c.(type) {
+	case *arrayContainer:
+		for k := 1; k < len(c.content); k++ {
+			if c.content[k] <= c.content[k-1] {
+				return false
+			}
+		}
+	case *runContainer:
+		for k := 1; k < len(c.runs); k++ {
+			if c.runs[k].start <= c.runs[k-1].last {
+				return false
+			}
+		}
+	case *bitmapContainer:
+		// 基数不超过arrayMaxSize时必须以数组容器存放
+		if c.card <= arrayMaxSize {
+			return false
+		}
+	}
+	return c.cardinality() == card
+}
+
+// MarshalBinary 实现encoding.BinaryMarshaler
+func (b *Bitmap) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := b.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary 实现encoding.BinaryUnmarshaler
+func (b *Bitmap) UnmarshalBinary(data []byte) error {
+	var _, err = b.ReadFrom(bytes.NewReader(data))
+	return err
+}
@@ -0,0 +1,178 @@
+package heap
+
+// Item 堆中带优先级的元素
+type Item struct {
+	// Value 元素值
+	Value interface{}
+	// Priority 优先级，越小越靠近堆顶
+	Priority int64
+	// 在堆中的下标，不在堆中时为-1
+	index int
+}
+
+// NewItem new item
+func NewItem(value interface{}, priority int64) *Item {
+	return &Item{Value: value, Priority: priority, index: -1}
+}
+
+// Index 元素在堆中的下标，不在堆中时为-1
+func (it *Item) Index() int {
+	return it.index
+}
+
+// ItemHeap 按Priority排序的小顶堆
+// 元素记录了自身在堆中的下标，修改Priority后可以通过Fix在O(logn)内恢复堆序
+// 指定容量后为有界堆，通过Offer只保留优先级最大的cap个元素，可用于求TopK
+type ItemHeap struct {
+	items []*Item
+	// 容量，小于等于0表示不限制
+	cap int
+}
+
+// NewItemHeap new item heap
+func NewItemHeap(items ...*Item) *ItemHeap {
+	var hp = &ItemHeap{items: items}
+	for i := range items {
+		items[i].index = i
+	}
+	hp.initItemHeap()
+	return hp
+}
+
+// NewBoundedItemHeap 创建容量为k的有界堆
+func NewBoundedItemHeap(k int) *ItemHeap {
+	return &ItemHeap{items: make([]*Item, 0, k), cap: k}
+}
+
+// Len len
+func (h *ItemHeap) Len() int {
+	return len(h.items)
+}
+
+// Empty empty
+func (h *ItemHeap) Empty() bool {
+	return h.Len() == 0
+}
+
+// Full 有界堆是否已满，无界堆始终返回false
+func (h *ItemHeap) Full() bool {
+	return h.cap > 0 && h.Len() >= h.cap
+}
+
+// Top top
+func (h *ItemHeap) Top() (*Item, bool) {
+	if h.Empty() {
+		return nil, false
+	}
+	return h.items[0], true
+}
+
+// Pop pop
+func (h *ItemHeap) Pop() (*Item, bool) {
+	if h.Empty() {
+		return nil, false
+	}
+	return h.Remove(0)
+}
+
+// Remove remove
+func (h *ItemHeap) Remove(index int) (*Item, bool) {
+	if index < 0 || index >= h.Len() {
+		return nil, false
+	}
+	var (
+		ans  = h.items[index]
+		last = h.Len() - 1
+	)
+	h.swap(index, last)
+	h.items[last] = nil
+	h.items = h.items[:last]
+	if index < last {
+		// 实际上down或up只会执行一个
+		h.down(index)
+		h.up(index)
+	}
+	ans.index = -1
+	return ans, true
+}
+
+// Push push
+func (h *ItemHeap) Push(item *Item) {
+	item.index = h.Len()
+	h.items = append(h.items, item)
+	h.up(item.index)
+}
+
+// Offer 有界堆中加入item：未满时直接加入；已满时若item优先级高于堆顶则替换堆顶
+// 返回被淘汰的元素（可能是item本身），没有元素被淘汰时返回false
+func (h *ItemHeap) Offer(item *Item) (*Item, bool) {
+	if !h.Full() {
+		h.Push(item)
+		return nil, false
+	}
+	if item.Priority <= h.items[0].Priority {
+		return item, true
+	}
+	var evicted = h.items[0]
+	evicted.index = -1
+	item.index = 0
+	h.items[0] = item
+	h.down(0)
+	return evicted, true
+}
+
+// Fix 元素的Priority被修改后恢复堆序
+func (h *ItemHeap) Fix(item *Item) {
+	if item.index < 0 || item.index >= h.Len() || h.items[item.index] != item {
+		return
+	}
+	h.down(item.index)
+	h.up(item.index)
+}
+
+// Items 堆中所有元素，顺序不保证有序，调用方不应修改
+func (h *ItemHeap) Items() []*Item {
+	return h.items
+}
+
+func (h *ItemHeap) initItemHeap() {
+	for i := h.Len() >> 1; i >= 0; i-- {
+		h.down(i)
+	}
+}
+
+func (h *ItemHeap) swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].index = i
+	h.items[j].index = j
+}
+
+func (h *ItemHeap) down(u int) {
+	var (
+		t     = u
+		left  = 2*u + 1
+		right = left + 1
+	)
+	// 在根节点、左节点、右节点三个节点中选择最小的节点
+	if left < h.Len() && h.items[left].Priority < h.items[t].Priority {
+		t = left
+	}
+	if right < h.Len() && h.items[right].Priority < h.items[t].Priority {
+		t = right
+	}
+	if t != u {
+		h.swap(t, u)
+		h.down(t)
+	}
+}
+
+func (h *ItemHeap) up(u int) {
+	for {
+		var root = (u - 1) >> 1
+		if root < 0 || h.items[root].Priority <= h.items[u].Priority {
+			break
+		}
+		h.swap(u, root)
+		u = root
+	}
+}
@@ -0,0 +1,130 @@
+package heap
+
+import (
+	"reflect"
+	"testing"
+)
+
+// popAll 依次弹出所有元素的Value
+func popAll(h *ItemHeap) []interface{} {
+	var got []interface{}
+	for !h.Empty() {
+		var it, _ = h.Pop()
+		if it.Index() != -1 {
+			panic("popped item still has an index")
+		}
+		got = append(got, it.Value)
+	}
+	return got
+}
+
+func TestItemHeap_Order(t *testing.T) {
+	tests := []struct {
+		name       string
+		priorities []int64
+		want       []interface{}
+	}{
+		{name: "empty", priorities: nil, want: nil},
+		{name: "single", priorities: []int64{3}, want: []interface{}{0}},
+		{name: "unordered", priorities: []int64{5, 1, 4, 2, 3}, want: []interface{}{1, 3, 4, 2, 0}},
+		{name: "descending", priorities: []int64{9, 7, 5, 3, 1, -1}, want: []interface{}{5, 4, 3, 2, 1, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				built  []*Item
+				pushed = NewItemHeap()
+			)
+			for i, p := range tt.priorities {
+				built = append(built, NewItem(i, p))
+				pushed.Push(NewItem(i, p))
+			}
+			if got := popAll(NewItemHeap(built...)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewItemHeap() pop order = %v, want %v", got, tt.want)
+			}
+			if got := popAll(pushed); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Push() pop order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestItemHeap_Offer(t *testing.T) {
+	tests := []struct {
+		name        string
+		k           int
+		priorities  []int64
+		wantEvicted []interface{}
+		want        []interface{}
+	}{
+		{name: "not full", k: 5, priorities: []int64{3, 1, 2}, wantEvicted: nil, want: []interface{}{1, 2, 0}},
+		{name: "evict top", k: 2, priorities: []int64{1, 2, 3, 4}, wantEvicted: []interface{}{0, 1}, want: []interface{}{2, 3}},
+		{name: "reject smaller", k: 2, priorities: []int64{5, 6, 1, 5}, wantEvicted: []interface{}{2, 3}, want: []interface{}{0, 1}},
+		{name: "top k", k: 3, priorities: []int64{4, 9, 1, 7, 3, 8}, wantEvicted: []interface{}{2, 4, 0}, want: []interface{}{3, 5, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				h       = NewBoundedItemHeap(tt.k)
+				evicted []interface{}
+			)
+			for i, p := range tt.priorities {
+				var item = NewItem(i, p)
+				if it, ok := h.Offer(item); ok {
+					if it.Index() != -1 {
+						t.Fatalf("Offer() evicted %v with index %v", it.Value, it.Index())
+					}
+					evicted = append(evicted, it.Value)
+				}
+				if h.Len() > tt.k {
+					t.Fatalf("Len() = %v, exceeds capacity %v", h.Len(), tt.k)
+				}
+			}
+			if !reflect.DeepEqual(evicted, tt.wantEvicted) {
+				t.Errorf("Offer() evicted = %v, want %v", evicted, tt.wantEvicted)
+			}
+			if got := popAll(h); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pop order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestItemHeap_Fix(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   int
+		priority int64
+		want     []interface{}
+	}{
+		{name: "decrease to top", target: 4, priority: 0, want: []interface{}{4, 0, 1, 2, 3}},
+		{name: "increase top", target: 0, priority: 100, want: []interface{}{1, 2, 3, 4, 0}},
+		{name: "move to middle", target: 1, priority: 45, want: []interface{}{0, 2, 3, 1, 4}},
+		{name: "unchanged", target: 2, priority: 30, want: []interface{}{0, 1, 2, 3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var items []*Item
+			for i := 0; i < 5; i++ {
+				items = append(items, NewItem(i, int64(10*(i+1))))
+			}
+			var h = NewItemHeap(items...)
+			items[tt.target].Priority = tt.priority
+			h.Fix(items[tt.target])
+			for i, it := range h.Items() {
+				if it.Index() != i {
+					t.Fatalf("Items()[%d].Index() = %v", i, it.Index())
+				}
+			}
+			if got := popAll(h); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pop order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	// 不在堆中的元素调用Fix不产生影响
+	var h = NewItemHeap(NewItem("a", 1))
+	h.Fix(NewItem("b", 0))
+	if top, _ := h.Top(); top.Value != "a" || h.Len() != 1 {
+		t.Errorf("Fix() on foreign item changed the heap")
+	}
+}
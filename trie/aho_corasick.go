@@ -0,0 +1,248 @@
+package trie
+
+import (
+	"io"
+)
+
+// MatchKind 多模式匹配的方式
+type MatchKind int32
+
+const (
+	// MatchOverlapping 报告所有匹配，包括相互重叠的匹配
+	MatchOverlapping MatchKind = iota
+	// MatchLeftmostLongest 从左到右报告互不重叠的匹配，起点相同时取最长的模式
+	MatchLeftmostLongest
+)
+
+// ACMatch 一次匹配：文本中[Start, End)字节区间为第Pattern个模式
+type ACMatch struct {
+	Pattern int
+	Start   int
+	End     int
+}
+
+// AhoCorasick AC自动机
+// 在所有模式组成的前缀树上为每个节点增加失败指针（最长的、同时是某个模式前缀的真后缀），扫描一遍文本即可找到所有模式的出现
+type AhoCorasick struct {
+	patterns []string
+	nodes    []acNode
+	// 根节点的完整转移表，失配时不必再查失败指针
+	rootNext [256]int32
+	kind     MatchKind
+}
+
+type acNode struct {
+	next map[byte]int32
+	fail int32
+	// 以该节点结尾的模式下标，-1表示没有
+	pattern int32
+	// 沿失败指针最近的有模式结尾的节点，-1表示没有
+	output int32
+	// 节点深度，即对应前缀的长度
+	depth int32
+}
+
+// NewAhoCorasick 由patterns构建AC自动机，默认报告所有重叠的匹配
+// 空模式会被忽略，重复的模式只报告第一次出现的下标
+func NewAhoCorasick(patterns []string) *AhoCorasick {
+	var ac = &AhoCorasick{patterns: patterns}
+	ac.nodes = append(ac.nodes, newACNode(0))
+	for i, p := range patterns {
+		if len(p) == 0 {
+			continue
+		}
+		var s int32
+		for j := 0; j < len(p); j++ {
+			var t, has = ac.nodes[s].next[p[j]]
+			if !has {
+				t = int32(len(ac.nodes))
+				ac.nodes = append(ac.nodes, newACNode(int32(j+1)))
+				ac.nodes[s].next[p[j]] = t
+			}
+			s = t
+		}
+		if ac.nodes[s].pattern < 0 {
+			ac.nodes[s].pattern = int32(i)
+		}
+	}
+	ac.build()
+	return ac
+}
+
+func newACNode(depth int32) acNode {
+	return acNode{next: make(map[byte]int32), pattern: -1, output: -1, depth: depth}
+}
+
+// build 按层次遍历计算失败指针与输出链
+func (ac *AhoCorasick) build() {
+	var queue = make([]int32, 0, len(ac.nodes))
+	for c, t := range ac.nodes[0].next {
+		ac.rootNext[c] = t
+		queue = append(queue, t)
+	}
+	for len(queue) > 0 {
+		var u = queue[0]
+		queue = queue[1:]
+		for c, v := range ac.nodes[u].next {
+			var f int32
+			if u != 0 {
+				f = ac.transition(ac.nodes[u].fail, c)
+			}
+			ac.nodes[v].fail = f
+			if ac.nodes[f].pattern >= 0 {
+				ac.nodes[v].output = f
+			} else {
+				ac.nodes[v].output = ac.nodes[f].output
+			}
+			queue = append(queue, v)
+		}
+	}
+}
+
+// transition 状态s读入字节c后的状态
+func (ac *AhoCorasick) transition(s int32, c byte) int32 {
+	for s != 0 {
+		if t, has := ac.nodes[s].next[c]; has {
+			return t
+		}
+		s = ac.nodes[s].fail
+	}
+	return ac.rootNext[c]
+}
+
+// WithMatchKind 指定匹配方式
+func (ac *AhoCorasick) WithMatchKind(kind MatchKind) {
+	ac.kind = kind
+}
+
+// Patterns 构建时的所有模式
+func (ac *AhoCorasick) Patterns() []string {
+	return ac.patterns
+}
+
+// FindAll 返回text中的所有匹配，按结束位置升序
+func (ac *AhoCorasick) FindAll(text string) []ACMatch {
+	var (
+		matches []ACMatch
+		s       = ac.newScanner()
+		fn      = func(m ACMatch) bool {
+			matches = append(matches, m)
+			return true
+		}
+	)
+	for i := 0; i < len(text); i++ {
+		s.feed(text[i], fn)
+	}
+	s.flush(fn)
+	return matches
+}
+
+// Contains text中是否出现了任一模式
+func (ac *AhoCorasick) Contains(text string) bool {
+	var s int32
+	for i := 0; i < len(text); i++ {
+		if s = ac.transition(s, text[i]); ac.nodes[s].pattern >= 0 || ac.nodes[s].output >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// FindReader 流式扫描r中的内容，每找到一个匹配调用一次fn，fn返回false时停止
+// 匹配位置为从流开头算起的字节偏移，内存占用与流的长度无关
+func (ac *AhoCorasick) FindReader(r io.Reader, fn func(ACMatch) bool) error {
+	var (
+		s   = ac.newScanner()
+		buf = make([]byte, 32*1024)
+	)
+	for {
+		var n, err = r.Read(buf)
+		for i := 0; i < n; i++ {
+			if !s.feed(buf[i], fn) {
+				return nil
+			}
+		}
+		if err == io.EOF {
+			s.flush(fn)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// acScanner 一次扫描的状态
+type acScanner struct {
+	ac    *AhoCorasick
+	state int32
+	// 已读入的字节数
+	pos int
+	// MatchLeftmostLongest下尚未确定的匹配
+	pending []ACMatch
+	// 上一个已报告匹配的结束位置
+	lastEnd int
+}
+
+func (ac *AhoCorasick) newScanner() *acScanner {
+	return &acScanner{ac: ac}
+}
+
+// feed 读入一个字节，fn返回false时停止并返回false
+func (s *acScanner) feed(c byte, fn func(ACMatch) bool) bool {
+	var ac = s.ac
+	s.state = ac.transition(s.state, c)
+	s.pos++
+	var u = s.state
+	if ac.nodes[u].pattern < 0 {
+		u = ac.nodes[u].output
+	}
+	for ; u >= 0; u = ac.nodes[u].output {
+		var m = ACMatch{Pattern: int(ac.nodes[u].pattern), Start: s.pos - int(ac.nodes[u].depth), End: s.pos}
+		if ac.kind == MatchOverlapping {
+			if !fn(m) {
+				return false
+			}
+		} else if m.Start >= s.lastEnd {
+			s.pending = append(s.pending, m)
+		}
+	}
+	if ac.kind == MatchOverlapping {
+		return true
+	}
+	// 之后的匹配起点都不小于pos-depth，早于它的匹配可以确定下来
+	return s.resolve(s.pos-int(ac.nodes[s.state].depth), fn)
+}
+
+// flush 文本结束，确定所有剩余的匹配
+func (s *acScanner) flush(fn func(ACMatch) bool) bool {
+	return s.resolve(s.pos+1, fn)
+}
+
+// resolve 按最左最长的规则报告起点小于bound的匹配
+func (s *acScanner) resolve(bound int, fn func(ACMatch) bool) bool {
+	for len(s.pending) > 0 {
+		var best = s.pending[0]
+		for _, m := range s.pending[1:] {
+			if m.Start < best.Start || (m.Start == best.Start && m.End > best.End) {
+				best = m
+			}
+		}
+		if best.Start >= bound {
+			return true
+		}
+		if !fn(best) {
+			return false
+		}
+		s.lastEnd = best.End
+		// 丢弃与已报告的匹配重叠的匹配
+		var rest = s.pending[:0]
+		for _, m := range s.pending {
+			if m.Start >= s.lastEnd {
+				rest = append(rest, m)
+			}
+		}
+		s.pending = rest
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package trie
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAhoCorasick_FindAll(t *testing.T) {
+	var patterns = []string{"he", "she", "his", "hers"}
+	tests := []struct {
+		name string
+		kind MatchKind
+		text string
+		want []ACMatch
+	}{
+		{
+			name: "overlapping",
+			kind: MatchOverlapping,
+			text: "ushers",
+			want: []ACMatch{{Pattern: 1, Start: 1, End: 4}, {Pattern: 0, Start: 2, End: 4}, {Pattern: 3, Start: 2, End: 6}},
+		},
+		{
+			name: "leftmost longest",
+			kind: MatchLeftmostLongest,
+			text: "ushers",
+			want: []ACMatch{{Pattern: 1, Start: 1, End: 4}},
+		},
+		{
+			name: "leftmost longest prefers longer",
+			kind: MatchLeftmostLongest,
+			text: "hershis",
+			want: []ACMatch{{Pattern: 3, Start: 0, End: 4}, {Pattern: 2, Start: 4, End: 7}},
+		},
+		{
+			name: "no match",
+			kind: MatchOverlapping,
+			text: "abc",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ac = NewAhoCorasick(patterns)
+			ac.WithMatchKind(tt.kind)
+			if got := ac.FindAll(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindAll(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAhoCorasick_LeftmostLongest(t *testing.T) {
+	// 较短的匹配"ab"之后被更长的"abcd"取代前，不能丢失之后的"c"
+	var ac = NewAhoCorasick([]string{"abcd", "ab", "c", "e"})
+	ac.WithMatchKind(MatchLeftmostLongest)
+	var want = []ACMatch{{Pattern: 1, Start: 0, End: 2}, {Pattern: 2, Start: 2, End: 3}, {Pattern: 3, Start: 3, End: 4}}
+	if got := ac.FindAll("abce"); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAll(\"abce\") = %v, want %v", got, want)
+	}
+	want = []ACMatch{{Pattern: 0, Start: 0, End: 4}, {Pattern: 1, Start: 4, End: 6}}
+	if got := ac.FindAll("abcdab"); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAll(\"abcdab\") = %v, want %v", got, want)
+	}
+}
+
+func TestAhoCorasick_FindReader(t *testing.T) {
+	var ac = NewAhoCorasick([]string{"needle", "中文"})
+	var text = strings.Repeat("x", 40000) + "needle" + strings.Repeat("y", 30000) + "中文"
+	var got []ACMatch
+	if err := ac.FindReader(strings.NewReader(text), func(m ACMatch) bool {
+		got = append(got, m)
+		return true
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, ac.FindAll(text)) || len(got) != 2 || got[0].Start != 40000 {
+		t.Fatalf("FindReader() = %v", got)
+	}
+	if !ac.Contains(text) || ac.Contains("needl") {
+		t.Fatalf("Contains() mismatch")
+	}
+}
@@ -0,0 +1,58 @@
+package trie
+
+import (
+	"sort"
+)
+
+// Alphabet 数组前缀树使用的字母表
+type Alphabet struct {
+	// 字母表中的字符，按字节升序存放，使子节点下标顺序与字典序一致
+	chars []byte
+	// index[c] 字符c在chars中的下标，不在字母表中时为-1
+	index [256]int16
+}
+
+// LowercaseAlphabet 小写英文字母a-z
+var LowercaseAlphabet = mustAlphabet("abcdefghijklmnopqrstuvwxyz")
+
+// NewAlphabet 由chars中的字符创建字母表，chars不能为空且不能有重复字符
+func NewAlphabet(chars string) (*Alphabet, error) {
+	if len(chars) == 0 {
+		return nil, ErrInvalidAlphabet
+	}
+	var a = &Alphabet{chars: []byte(chars)}
+	sort.Slice(a.chars, func(i, j int) bool { return a.chars[i] < a.chars[j] })
+	for i := range a.index {
+		a.index[i] = -1
+	}
+	for i, c := range a.chars {
+		if a.index[c] != -1 {
+			return nil, ErrInvalidAlphabet
+		}
+		a.index[c] = int16(i)
+	}
+	return a, nil
+}
+
+func mustAlphabet(chars string) *Alphabet {
+	var a, err = NewAlphabet(chars)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// Size 字母表中的字符个数
+func (a *Alphabet) Size() int {
+	return len(a.chars)
+}
+
+// Contains 字母表是否包含word中的所有字符
+func (a *Alphabet) Contains(word string) bool {
+	for i := 0; i < len(word); i++ {
+		if a.index[word[i]] < 0 {
+			return false
+		}
+	}
+	return true
+}
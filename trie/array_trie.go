@@ -1,28 +1,68 @@
 package trie
 
-// ArrayTrie 数组前缀树
+// ArrayTrie 数组前缀树，子节点按字符在字母表中的下标存放
 type ArrayTrie struct {
-	isWord   bool
-	children [26]*ArrayTrie
+	isWord bool
+	// 以该节点为前缀的单词个数
+	count    int
+	alphabet *Alphabet
+	children []*ArrayTrie
 }
 
-var _ Trie = (*ArrayTrie)(nil)
+var (
+	_ ExtendedTrie = (*ArrayTrie)(nil)
+	_ walkable     = (*ArrayTrie)(nil)
+)
 
-// NewArrayTrie new arrayTrie
+// NewArrayTrie new arrayTrie，字母表为小写英文字母
 func NewArrayTrie() *ArrayTrie {
-	return &ArrayTrie{children: [26]*ArrayTrie{}}
+	return newArrayTrie(LowercaseAlphabet)
+}
+
+// NewArrayTrieWithAlphabet 创建使用字母表chars的数组前缀树
+func NewArrayTrieWithAlphabet(chars string) (*ArrayTrie, error) {
+	var alphabet, err = NewAlphabet(chars)
+	if err != nil {
+		return nil, err
+	}
+	return newArrayTrie(alphabet), nil
+}
+
+func newArrayTrie(alphabet *Alphabet) *ArrayTrie {
+	return &ArrayTrie{alphabet: alphabet, children: make([]*ArrayTrie, alphabet.Size())}
+}
+
+// Alphabet 字母表
+func (a *ArrayTrie) Alphabet() *Alphabet {
+	return a.alphabet
 }
 
 // Insert 往前缀树中添加一个元素word
+// 含有字母表以外字符的word会被忽略，需要感知这种情况时使用TryInsert
 func (a *ArrayTrie) Insert(word string) {
+	_ = a.TryInsert(word)
+}
+
+// TryInsert 往前缀树中添加一个元素word，word含有字母表以外的字符时返回ErrOutOfAlphabet且不做修改
+func (a *ArrayTrie) TryInsert(word string) error {
+	if !a.alphabet.Contains(word) {
+		return ErrOutOfAlphabet
+	}
+	if a.Search(word) {
+		return nil
+	}
 	var curNode = a
+	curNode.count++
 	for i := 0; i < len(word); i++ {
-		if curNode.children[word[i]-'a'] == nil {
-			curNode.children[word[i]-'a'] = NewArrayTrie()
+		var idx = a.alphabet.index[word[i]]
+		if curNode.children[idx] == nil {
+			curNode.children[idx] = newArrayTrie(a.alphabet)
 		}
-		curNode = curNode.children[word[i]-'a']
+		curNode = curNode.children[idx]
+		curNode.count++
 	}
 	curNode.isWord = true
+	return nil
 }
 
 // Search 查找前缀树中是否元素word
@@ -32,24 +72,103 @@ func (a *ArrayTrie) Search(word string) bool {
 }
 
 func (a *ArrayTrie) search(word string) (*ArrayTrie, bool) {
+	var curNode = a.find(word)
+	if curNode == nil {
+		return nil, false
+	}
+	return curNode, curNode.isWord
+}
+
+// find 沿prefix向下查找节点，不存在或含有字母表以外的字符时返回nil
+func (a *ArrayTrie) find(prefix string) *ArrayTrie {
 	var curNode = a
-	for i := 0; i < len(word); i++ {
-		if curNode.children[word[i]-'a'] == nil {
-			return nil, false
+	for i := 0; i < len(prefix); i++ {
+		var idx = a.alphabet.index[prefix[i]]
+		if idx < 0 || curNode.children[idx] == nil {
+			return nil
 		}
-		curNode = curNode.children[word[i]-'a']
+		curNode = curNode.children[idx]
 	}
-	return curNode, curNode.isWord
+	return curNode
 }
 
 // HasPrefix 查询前缀树中是否存在前缀prefix
 func (a *ArrayTrie) HasPrefix(prefix string) bool {
+	return a.find(prefix) != nil
+}
+
+// Delete 从前缀树中删除元素word，并剪除不再有单词经过的分支，word不存在时返回false
+func (a *ArrayTrie) Delete(word string) bool {
+	if !a.Search(word) {
+		return false
+	}
 	var curNode = a
-	for i := 0; i < len(prefix); i++ {
-		if curNode.children[prefix[i]-'a'] == nil {
+	curNode.count--
+	for i := 0; i < len(word); i++ {
+		var (
+			idx  = a.alphabet.index[word[i]]
+			next = curNode.children[idx]
+		)
+		next.count--
+		if next.count == 0 {
+			curNode.children[idx] = nil
+			return true
+		}
+		curNode = next
+	}
+	curNode.isWord = false
+	return true
+}
+
+// Len 前缀树中的单词个数
+func (a *ArrayTrie) Len() int {
+	return a.count
+}
+
+// CountPrefix 以prefix为前缀的单词个数
+func (a *ArrayTrie) CountPrefix(prefix string) int {
+	if curNode := a.find(prefix); curNode != nil {
+		return curNode.count
+	}
+	return 0
+}
+
+// KeysWithPrefix 按字典序返回以prefix为前缀的至多limit个单词，limit<=0时返回全部
+func (a *ArrayTrie) KeysWithPrefix(prefix string, limit int) []string {
+	return keysWithPrefix(a, prefix, limit)
+}
+
+// Walk 按字典序遍历以prefix为前缀的单词，fn返回false时停止遍历
+func (a *ArrayTrie) Walk(prefix string, fn func(word string) bool) {
+	if curNode := a.find(prefix); curNode != nil {
+		walkWords(curNode, prefix, "", false, fn)
+	}
+}
+
+// WalkAfter 按字典序遍历以prefix为前缀且大于after的单词，fn返回false时停止遍历
+func (a *ArrayTrie) WalkAfter(prefix, after string, fn func(word string) bool) {
+	if curNode := a.find(prefix); curNode != nil {
+		walkWords(curNode, prefix, after, true, fn)
+	}
+}
+
+func (a *ArrayTrie) isKey() bool {
+	return a.isWord
+}
+
+func (a *ArrayTrie) eachChild(fn func(label string, child walkNode) bool) bool {
+	// 字母表按字节升序存放，下标顺序即字典序
+	for i, child := range a.children {
+		if child != nil && !fn(byteLabels[a.alphabet.chars[i]], child) {
 			return false
 		}
-		curNode = curNode.children[prefix[i]-'a']
 	}
 	return true
 }
+
+func (a *ArrayTrie) prefixNode(prefix string) (walkNode, string) {
+	if curNode := a.find(prefix); curNode != nil {
+		return curNode, prefix
+	}
+	return nil, ""
+}
@@ -0,0 +1,204 @@
+package trie
+
+import (
+	"sort"
+)
+
+// Suggestion 补全建议
+type Suggestion struct {
+	Word  string
+	Score float64
+}
+
+// before a是否排在b之前：分数高的在前，分数相同时按字典序
+func (a Suggestion) before(b Suggestion) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	return a.Word < b.Word
+}
+
+// Autocomplete 带权重的前K个补全
+// 每个节点缓存子树中分数最高的K个单词，Suggest只需找到前缀对应的节点后取缓存，时间复杂度为O(len(prefix)+k)
+type Autocomplete struct {
+	k    int
+	root *autocompleteNode
+	size int
+}
+
+type autocompleteNode struct {
+	isWord bool
+	score  float64
+	// 子树中分数最高的至多k个单词，按before排序
+	top      []Suggestion
+	children map[byte]*autocompleteNode
+}
+
+// NewAutocomplete 创建每个前缀最多缓存k个补全的索引
+func NewAutocomplete(k int) *Autocomplete {
+	if k < 1 {
+		k = 1
+	}
+	return &Autocomplete{k: k, root: newAutocompleteNode()}
+}
+
+func newAutocompleteNode() *autocompleteNode {
+	return &autocompleteNode{children: make(map[byte]*autocompleteNode)}
+}
+
+// K 每个前缀最多缓存的补全个数
+func (a *Autocomplete) K() int {
+	return a.k
+}
+
+// Len 单词个数
+func (a *Autocomplete) Len() int {
+	return a.size
+}
+
+// find 沿prefix向下查找节点，不存在时返回nil
+func (a *Autocomplete) find(prefix string) *autocompleteNode {
+	var curNode = a.root
+	for i := 0; i < len(prefix); i++ {
+		if curNode = curNode.children[prefix[i]]; curNode == nil {
+			return nil
+		}
+	}
+	return curNode
+}
+
+// Score 单词word的分数
+func (a *Autocomplete) Score(word string) (float64, bool) {
+	if curNode := a.find(word); curNode != nil && curNode.isWord {
+		return curNode.score, true
+	}
+	return 0, false
+}
+
+// Set 设置单词word的分数，不存在时插入
+func (a *Autocomplete) Set(word string, score float64) {
+	var (
+		curNode = a.root
+		path    = make([]*autocompleteNode, 0, len(word)+1)
+	)
+	path = append(path, curNode)
+	for i := 0; i < len(word); i++ {
+		var next = curNode.children[word[i]]
+		if next == nil {
+			next = newAutocompleteNode()
+			curNode.children[word[i]] = next
+		}
+		curNode = next
+		path = append(path, curNode)
+	}
+	var existed, old = curNode.isWord, curNode.score
+	if !existed {
+		a.size++
+	}
+	curNode.isWord, curNode.score = true, score
+	var s = Suggestion{Word: word, Score: score}
+	if !existed || score >= old {
+		// 分数提高时其他单词的排名不变，只需把该单词放到新的位置
+		for _, n := range path {
+			a.upsert(n, s)
+		}
+		return
+	}
+	// 分数降低时原来排在它之后的单词可能进入前K，自底向上重新计算包含它的缓存
+	// 某个节点的缓存不包含它时，祖先节点的缓存也不可能包含它
+	for i := len(path) - 1; i >= 0 && a.contains(path[i], word); i-- {
+		a.recompute(path[i], word[:i])
+	}
+}
+
+// Delete 删除单词word并剪除不再有单词的分支，word不存在时返回false
+func (a *Autocomplete) Delete(word string) bool {
+	var (
+		curNode = a.root
+		path    = make([]*autocompleteNode, 0, len(word)+1)
+	)
+	path = append(path, curNode)
+	for i := 0; i < len(word); i++ {
+		if curNode = curNode.children[word[i]]; curNode == nil {
+			return false
+		}
+		path = append(path, curNode)
+	}
+	if !curNode.isWord {
+		return false
+	}
+	curNode.isWord, curNode.score = false, 0
+	a.size--
+	for i := len(path) - 1; i >= 0; i-- {
+		var n = path[i]
+		if i > 0 && !n.isWord && len(n.children) == 0 {
+			delete(path[i-1].children, word[i-1])
+			continue
+		}
+		if !a.contains(n, word) {
+			break
+		}
+		a.recompute(n, word[:i])
+	}
+	return true
+}
+
+// contains 节点的缓存中是否有单词word
+func (a *Autocomplete) contains(n *autocompleteNode, word string) bool {
+	for _, s := range n.top {
+		if s.Word == word {
+			return true
+		}
+	}
+	return false
+}
+
+// upsert 将s放入节点的缓存中，已存在时更新其位置，超过k个时丢弃最后一个
+func (a *Autocomplete) upsert(n *autocompleteNode, s Suggestion) {
+	for i := range n.top {
+		if n.top[i].Word == s.Word {
+			n.top = append(n.top[:i], n.top[i+1:]...)
+			break
+		}
+	}
+	var i = sort.Search(len(n.top), func(i int) bool { return s.before(n.top[i]) })
+	if i == a.k {
+		return
+	}
+	n.top = append(n.top, Suggestion{})
+	copy(n.top[i+1:], n.top[i:])
+	n.top[i] = s
+	if len(n.top) > a.k {
+		n.top = n.top[:a.k]
+	}
+}
+
+// recompute 由节点自身的单词key与子节点的缓存重新计算节点的缓存，子节点的缓存必须是最新的
+func (a *Autocomplete) recompute(n *autocompleteNode, key string) {
+	var candidates []Suggestion
+	if n.isWord {
+		candidates = append(candidates, Suggestion{Word: key, Score: n.score})
+	}
+	for _, child := range n.children {
+		candidates = append(candidates, child.top...)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].before(candidates[j]) })
+	if len(candidates) > a.k {
+		candidates = candidates[:a.k]
+	}
+	n.top = candidates
+}
+
+// Suggest 以prefix为前缀的分数最高的至多k个单词，k超过K()时按K()计算
+func (a *Autocomplete) Suggest(prefix string, k int) []Suggestion {
+	var curNode = a.find(prefix)
+	if curNode == nil || k <= 0 {
+		return nil
+	}
+	if k > len(curNode.top) {
+		k = len(curNode.top)
+	}
+	var ans = make([]Suggestion, k)
+	copy(ans, curNode.top)
+	return ans
+}
@@ -0,0 +1,83 @@
+package trie
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestAutocomplete(t *testing.T) {
+	var a = NewAutocomplete(3)
+	var scores = map[string]float64{"apple": 5, "app": 3, "application": 4, "apply": 1, "ape": 2, "banana": 10}
+	for w, s := range scores {
+		a.Set(w, s)
+	}
+	var want = []Suggestion{{Word: "apple", Score: 5}, {Word: "application", Score: 4}, {Word: "app", Score: 3}}
+	if got := a.Suggest("ap", 5); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Suggest(\"ap\", 5) = %v, want %v", got, want)
+	}
+	// 分数提高
+	a.Set("apply", 6)
+	want = []Suggestion{{Word: "apply", Score: 6}, {Word: "apple", Score: 5}}
+	if got := a.Suggest("appl", 2); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Suggest(\"appl\", 2) = %v, want %v", got, want)
+	}
+	// 分数降低后原来排在前K之外的单词补上
+	a.Set("apply", 0)
+	a.Set("apple", 0)
+	want = []Suggestion{{Word: "application", Score: 4}, {Word: "app", Score: 3}, {Word: "ape", Score: 2}}
+	if got := a.Suggest("a", 3); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Suggest(\"a\", 3) = %v, want %v", got, want)
+	}
+	if !a.Delete("application") || a.Delete("application") || a.Delete("appl") {
+		t.Fatalf("Delete() mismatch")
+	}
+	want = []Suggestion{{Word: "app", Score: 3}, {Word: "apple", Score: 0}, {Word: "apply", Score: 0}}
+	if got := a.Suggest("app", 3); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Suggest(\"app\", 3) = %v, want %v", got, want)
+	}
+	if got := a.Suggest("applic", 3); got != nil {
+		t.Fatalf("Suggest(\"applic\", 3) = %v, want nil", got)
+	}
+	if s, ok := a.Score("banana"); !ok || s != 10 || a.Len() != 5 {
+		t.Fatalf("Score(\"banana\") = %v, %v, Len() = %v", s, ok, a.Len())
+	}
+}
+
+func TestAutocomplete_Random(t *testing.T) {
+	var (
+		r      = rand.New(rand.NewSource(1))
+		a      = NewAutocomplete(4)
+		scores = make(map[string]float64)
+	)
+	for i := 0; i < 5000; i++ {
+		var b = make([]byte, 1+r.Intn(4))
+		for j := range b {
+			b[j] = byte('a' + r.Intn(3))
+		}
+		var w = string(b)
+		if r.Intn(4) == 0 {
+			delete(scores, w)
+			a.Delete(w)
+		} else {
+			scores[w] = float64(r.Intn(20))
+			a.Set(w, scores[w])
+		}
+		var prefix = w[:r.Intn(len(w)+1)]
+		var want []Suggestion
+		for w, s := range scores {
+			if strings.HasPrefix(w, prefix) {
+				want = append(want, Suggestion{Word: w, Score: s})
+			}
+		}
+		sort.Slice(want, func(i, j int) bool { return want[i].before(want[j]) })
+		if len(want) > 4 {
+			want = want[:4]
+		}
+		if got := a.Suggest(prefix, 4); !reflect.DeepEqual(got, want) && (len(got) != 0 || len(want) != 0) {
+			t.Fatalf("Suggest(%q, 4) = %v, want %v", prefix, got, want)
+		}
+	}
+}
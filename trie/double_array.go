@@ -0,0 +1,381 @@
+package trie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+// DoubleArray 双数组前缀树，由有序的键集合一次性构建，构建后只读
+// 状态s读入字节c后转移到t=base[s]+c+1，当且仅当check[t]==s时转移存在；
+// 单词结尾用编码0的子节点表示，该叶子节点的base为-(键下标+1)
+// 所有节点存放在两个int32数组中，内存紧凑，适合几十万词的静态词典，并可从二进制格式快速加载
+type DoubleArray struct {
+	base  []int32
+	check []int32
+	// values[i] 第i个键的值
+	values []int
+}
+
+// PrefixMatch 公共前缀查询的结果：文本的前Length个字节是词典中的键，其值为Value
+type PrefixMatch struct {
+	Length int
+	Value  int
+}
+
+// daNode 构建时的节点，对应有序键集合中[left, right)区间的键
+type daNode struct {
+	code        int32
+	depth       int
+	left, right int
+}
+
+// daBuilder 双数组构建器
+type daBuilder struct {
+	keys  []string
+	base  []int32
+	check []int32
+	// 查找空闲位置的起点，之前的位置几乎已被占满
+	nextCheckPos int
+	// 已使用的最大下标
+	maxIndex int
+}
+
+// BuildDoubleArray 由严格递增的键集合keys构建双数组前缀树
+// values为nil时第i个键的值为i，否则长度必须与keys相同
+func BuildDoubleArray(keys []string, values []int) (*DoubleArray, error) {
+	if values != nil && len(values) != len(keys) {
+		return nil, ErrValuesLength
+	}
+	for i := 1; i < len(keys); i++ {
+		if keys[i-1] >= keys[i] {
+			return nil, ErrUnsortedKeys
+		}
+	}
+	var b = &daBuilder{keys: keys}
+	b.resize(1024)
+	b.check[0] = 0
+	if len(keys) > 0 {
+		b.base[0] = b.insert(0, b.fetch(daNode{right: len(keys)}))
+	}
+	var da = &DoubleArray{
+		base:   b.base[:b.maxIndex+1],
+		check:  b.check[:b.maxIndex+1],
+		values: make([]int, len(keys)),
+	}
+	for i := range da.values {
+		if values != nil {
+			da.values[i] = values[i]
+		} else {
+			da.values[i] = i
+		}
+	}
+	return da, nil
+}
+
+// resize 扩容到至少n个位置，新位置均为空闲
+func (b *daBuilder) resize(n int) {
+	if n <= len(b.check) {
+		return
+	}
+	var size = 2 * len(b.check)
+	if size < n {
+		size = n
+	}
+	var (
+		base  = make([]int32, size)
+		check = make([]int32, size)
+	)
+	copy(base, b.base)
+	copy(check, b.check)
+	for i := len(b.check); i < size; i++ {
+		check[i] = -1
+	}
+	b.base, b.check = base, check
+}
+
+// fetch parent的所有子节点，按编码升序；键在该深度结束时编码为0，否则为下一个字节加1
+func (b *daBuilder) fetch(parent daNode) []daNode {
+	var (
+		siblings []daNode
+		prev     = int32(-1)
+	)
+	for i := parent.left; i < parent.right; i++ {
+		var (
+			key  = b.keys[i]
+			code int32
+		)
+		if len(key) > parent.depth {
+			code = int32(key[parent.depth]) + 1
+		}
+		if code != prev {
+			if len(siblings) > 0 {
+				siblings[len(siblings)-1].right = i
+			}
+			siblings = append(siblings, daNode{code: code, depth: parent.depth + 1, left: i})
+			prev = code
+		}
+	}
+	siblings[len(siblings)-1].right = parent.right
+	return siblings
+}
+
+// insert 为状态parent的子节点siblings找到可用的base，占用对应位置并递归构建子树
+func (b *daBuilder) insert(parent int32, siblings []daNode) int32 {
+	var (
+		pos     = b.nextCheckPos
+		begin   int
+		nonzero int
+		first   = true
+		last    = int(siblings[len(siblings)-1].code)
+	)
+	if p := int(siblings[0].code) + 1; pos < p {
+		pos = p
+	}
+	for ; ; pos++ {
+		b.resize(pos + 1)
+		if b.check[pos] >= 0 {
+			nonzero++
+			continue
+		}
+		if first {
+			b.nextCheckPos = pos
+			first = false
+		}
+		begin = pos - int(siblings[0].code)
+		b.resize(begin + last + 1)
+		var ok = true
+		for _, s := range siblings[1:] {
+			if b.check[begin+int(s.code)] >= 0 {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			break
+		}
+	}
+	// 起点之后的位置几乎被占满时，下次直接从当前位置开始查找
+	if float64(nonzero)/float64(pos-b.nextCheckPos+1) >= 0.95 {
+		b.nextCheckPos = pos
+	}
+	for _, s := range siblings {
+		b.check[begin+int(s.code)] = parent
+	}
+	if begin+last > b.maxIndex {
+		b.maxIndex = begin + last
+	}
+	for _, s := range siblings {
+		var t = int32(begin) + s.code
+		if s.code == 0 {
+			b.base[t] = -int32(s.left) - 1
+			continue
+		}
+		b.base[t] = b.insert(t, b.fetch(s))
+	}
+	return int32(begin)
+}
+
+// Len 键的个数
+func (da *DoubleArray) Len() int {
+	return len(da.values)
+}
+
+// Size 双数组的长度
+func (da *DoubleArray) Size() int {
+	return len(da.base)
+}
+
+// next 状态s读入字节c后的状态，不存在时返回-1
+func (da *DoubleArray) next(s int32, c byte) int32 {
+	var t = da.base[s] + int32(c) + 1
+	if uint32(t) >= uint32(len(da.check)) || da.check[t] != s {
+		return -1
+	}
+	return t
+}
+
+// value 状态s对应的键的值，s不是某个键的结尾时返回false
+func (da *DoubleArray) value(s int32) (int, bool) {
+	var t = da.base[s]
+	if uint32(t) >= uint32(len(da.check)) || da.check[t] != s || da.base[t] >= 0 {
+		return 0, false
+	}
+	return da.values[-da.base[t]-1], true
+}
+
+// ExactMatch 键key对应的值
+func (da *DoubleArray) ExactMatch(key string) (int, bool) {
+	var s int32
+	for i := 0; i < len(key); i++ {
+		if s = da.next(s, key[i]); s < 0 {
+			return 0, false
+		}
+	}
+	return da.value(s)
+}
+
+// Contains 是否存在键key
+func (da *DoubleArray) Contains(key string) bool {
+	var _, has = da.ExactMatch(key)
+	return has
+}
+
+// CommonPrefixSearch 词典中所有是text前缀的键，按长度升序
+func (da *DoubleArray) CommonPrefixSearch(text string) []PrefixMatch {
+	var (
+		matches []PrefixMatch
+		s       int32
+	)
+	for i := 0; ; i++ {
+		if v, has := da.value(s); has {
+			matches = append(matches, PrefixMatch{Length: i, Value: v})
+		}
+		if i == len(text) {
+			return matches
+		}
+		if s = da.next(s, text[i]); s < 0 {
+			return matches
+		}
+	}
+}
+
+// daMagic 二进制格式的魔数"DART"
+const daMagic uint32 = 0x44415254
+
+// MaxDoubleArraySize 反序列化时允许的最大双数组长度
+const MaxDoubleArraySize = 1 << 30
+
+// daHeader 二进制格式的头部，之后依次为base、check与values数组，均为小端序
+type daHeader struct {
+	Magic uint32
+	// 双数组长度
+	Size uint32
+	// 键的个数
+	Keys uint32
+}
+
+// BinaryLen 序列化后的字节数
+func (da *DoubleArray) BinaryLen() int {
+	return binary.Size(daHeader{}) + 8*len(da.base) + 8*len(da.values)
+}
+
+// WriteTo 序列化到w
+func (da *DoubleArray) WriteTo(w io.Writer) (int64, error) {
+	var header = daHeader{Magic: daMagic, Size: uint32(len(da.base)), Keys: uint32(len(da.values))}
+	if err := binary.Write(w, binary.LittleEndian, &header); err != nil {
+		return 0, err
+	}
+	if err := binary.Write(w, binary.LittleEndian, da.base); err != nil {
+		return 0, err
+	}
+	if err := binary.Write(w, binary.LittleEndian, da.check); err != nil {
+		return 0, err
+	}
+	var values = make([]int64, len(da.values))
+	for i, v := range da.values {
+		values[i] = int64(v)
+	}
+	if err := binary.Write(w, binary.LittleEndian, values); err != nil {
+		return 0, err
+	}
+	return int64(da.BinaryLen()), nil
+}
+
+// ReadFrom 从r反序列化，无需重新构建
+// 数组按块读入，不会按头部中的长度预先分配内存；出错时返回已读取的字节数
+func (da *DoubleArray) ReadFrom(r io.Reader) (int64, error) {
+	var head [12]byte
+	if n, err := io.ReadFull(r, head[:]); err != nil {
+		return int64(n), err
+	}
+	var (
+		header = daHeader{
+			Magic: binary.LittleEndian.Uint32(head[0:]),
+			Size:  binary.LittleEndian.Uint32(head[4:]),
+			Keys:  binary.LittleEndian.Uint32(head[8:]),
+		}
+		read = int64(len(head))
+	)
+	// 每个键至少对应一个叶子节点
+	if header.Magic != daMagic || header.Size == 0 || header.Size > MaxDoubleArraySize || header.Keys > header.Size {
+		return read, ErrInvalidData
+	}
+	var (
+		base   []int32
+		check  []int32
+		values []int64
+	)
+	for _, step := range []struct {
+		n, size int
+		decode  func(b []byte)
+	}{
+		{int(header.Size), 4, func(b []byte) { base = append(base, int32(binary.LittleEndian.Uint32(b))) }},
+		{int(header.Size), 4, func(b []byte) { check = append(check, int32(binary.LittleEndian.Uint32(b))) }},
+		{int(header.Keys), 8, func(b []byte) { values = append(values, int64(binary.LittleEndian.Uint64(b))) }},
+	} {
+		var n, err = readChunked(r, step.n, step.size, step.decode)
+		read += n
+		if err != nil {
+			return read, err
+		}
+	}
+	for i := range base {
+		// check为-1表示空闲位置，否则必须指向有效的父状态
+		if check[i] < -1 || check[i] >= int32(len(check)) {
+			return read, ErrInvalidData
+		}
+		// 叶子节点引用的键下标必须有效
+		if v := base[i]; v < 0 && int64(-v-1) >= int64(len(values)) {
+			return read, ErrInvalidData
+		}
+	}
+	da.base, da.check, da.values = base, check, make([]int, len(values))
+	for i, v := range values {
+		da.values[i] = int(v)
+	}
+	return read, nil
+}
+
+// daReadChunk 反序列化时每次读取的元素个数
+const daReadChunk = 4096
+
+// readChunked 按块从r读取n个size字节的元素，依次交给decode解码，返回读取的字节数
+func readChunked(r io.Reader, n, size int, decode func(b []byte)) (int64, error) {
+	var (
+		buf  = make([]byte, size*daReadChunk)
+		read int64
+	)
+	for n > 0 {
+		var c = daReadChunk
+		if n < c {
+			c = n
+		}
+		var m, err = io.ReadFull(r, buf[:c*size])
+		read += int64(m)
+		if err != nil {
+			return read, err
+		}
+		for i := 0; i < c; i++ {
+			decode(buf[i*size:])
+		}
+		n -= c
+	}
+	return read, nil
+}
+
+// MarshalBinary 序列化
+func (da *DoubleArray) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(da.BinaryLen())
+	if _, err := da.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary 反序列化
+func (da *DoubleArray) UnmarshalBinary(data []byte) error {
+	var _, err = da.ReadFrom(bytes.NewReader(data))
+	return err
+}
@@ -0,0 +1,156 @@
+package trie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestBuildDoubleArray(t *testing.T) {
+	if _, err := BuildDoubleArray([]string{"b", "a"}, nil); err != ErrUnsortedKeys {
+		t.Fatalf("BuildDoubleArray() error = %v, want %v", err, ErrUnsortedKeys)
+	}
+	if _, err := BuildDoubleArray([]string{"a", "a"}, nil); err != ErrUnsortedKeys {
+		t.Fatalf("BuildDoubleArray() error = %v, want %v", err, ErrUnsortedKeys)
+	}
+	if _, err := BuildDoubleArray([]string{"a"}, []int{1, 2}); err != ErrValuesLength {
+		t.Fatalf("BuildDoubleArray() error = %v, want %v", err, ErrValuesLength)
+	}
+	var da, err = BuildDoubleArray(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if da.Contains("") || da.Contains("a") || da.CommonPrefixSearch("abc") != nil {
+		t.Fatalf("empty DoubleArray mismatch")
+	}
+}
+
+func TestDoubleArray_Search(t *testing.T) {
+	var keys = []string{"", "中", "中国", "中国人", "中华", "a", "ab", "abc", "b"}
+	sort.Strings(keys)
+	var values = make([]int, len(keys))
+	for i := range values {
+		values[i] = 100 + i
+	}
+	var da, err = BuildDoubleArray(keys, values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, k := range keys {
+		if v, ok := da.ExactMatch(k); !ok || v != values[i] {
+			t.Errorf("ExactMatch(%q) = %v, %v, want %v", k, v, ok, values[i])
+		}
+	}
+	for _, k := range []string{"c", "abcd", "中国人民", "国"} {
+		if da.Contains(k) {
+			t.Errorf("Contains(%q) = true, want false", k)
+		}
+	}
+	var got = da.CommonPrefixSearch("中国人民")
+	var want = []PrefixMatch{
+		{Length: 0, Value: values[0]},
+		{Length: len("中"), Value: values[sort.SearchStrings(keys, "中")]},
+		{Length: len("中国"), Value: values[sort.SearchStrings(keys, "中国")]},
+		{Length: len("中国人"), Value: values[sort.SearchStrings(keys, "中国人")]},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CommonPrefixSearch() = %v, want %v", got, want)
+	}
+}
+
+func TestDoubleArray_Random(t *testing.T) {
+	var (
+		r   = rand.New(rand.NewSource(1))
+		set = make(map[string]bool)
+	)
+	for len(set) < 20000 {
+		var b = make([]byte, 1+r.Intn(12))
+		for i := range b {
+			b[i] = byte('a' + r.Intn(8))
+		}
+		set[string(b)] = true
+	}
+	var keys = make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var da, err = BuildDoubleArray(keys, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := da.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded = new(DoubleArray)
+	if err = loaded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	for i, k := range keys {
+		if v, ok := loaded.ExactMatch(k); !ok || v != i {
+			t.Fatalf("ExactMatch(%q) = %v, %v, want %v", k, v, ok, i)
+		}
+	}
+	for i := 0; i < 10000; i++ {
+		var b = make([]byte, 1+r.Intn(12))
+		for i := range b {
+			b[i] = byte('a' + r.Intn(9))
+		}
+		if got := loaded.Contains(string(b)); got != set[string(b)] {
+			t.Fatalf("Contains(%q) = %v, want %v", b, got, set[string(b)])
+		}
+	}
+	if loaded.Len() != len(keys) || loaded.Size() != da.Size() {
+		t.Fatalf("Len() = %v, Size() = %v", loaded.Len(), loaded.Size())
+	}
+	if err = loaded.UnmarshalBinary(data[:10]); err == nil {
+		t.Fatalf("UnmarshalBinary() of truncated data succeeded")
+	}
+	data[0] ^= 0xff
+	if err = loaded.UnmarshalBinary(data); err != ErrInvalidData {
+		t.Fatalf("UnmarshalBinary() error = %v, want %v", err, ErrInvalidData)
+	}
+}
+
+func TestDoubleArray_ReadFromInvalid(t *testing.T) {
+	var da, _ = BuildDoubleArray([]string{"a", "ab", "b"}, nil)
+	var valid, _ = da.MarshalBinary()
+	// 头部偏移：0 Magic, 4 Size, 8 Keys，之后为base、check与values
+	var corrupt = func(off int, v uint32) []byte {
+		var data = append([]byte(nil), valid...)
+		binary.LittleEndian.PutUint32(data[off:], v)
+		return data
+	}
+	var checkOff = 12 + 4*da.Size()
+	tests := []struct {
+		name     string
+		data     []byte
+		wantErr  error
+		wantRead int64
+	}{
+		{name: "truncated header", data: valid[:10], wantErr: io.ErrUnexpectedEOF, wantRead: 10},
+		{name: "zero size", data: corrupt(4, 0), wantErr: ErrInvalidData, wantRead: 12},
+		{name: "oversized", data: corrupt(4, math.MaxUint32), wantErr: ErrInvalidData, wantRead: 12},
+		{name: "more keys than nodes", data: corrupt(8, uint32(da.Size()+1)), wantErr: ErrInvalidData, wantRead: 12},
+		{name: "truncated arrays", data: corrupt(4, MaxDoubleArraySize), wantErr: io.ErrUnexpectedEOF, wantRead: int64(len(valid))},
+		{name: "check out of range", data: corrupt(checkOff+4, uint32(da.Size())), wantErr: ErrInvalidData, wantRead: int64(len(valid))},
+		{name: "negative check", data: corrupt(checkOff+4, math.MaxUint32-1), wantErr: ErrInvalidData, wantRead: int64(len(valid))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var n, err = new(DoubleArray).ReadFrom(bytes.NewReader(tt.data))
+			if err != tt.wantErr || n != tt.wantRead {
+				t.Fatalf("ReadFrom() = %v, %v, want %v, %v", n, err, tt.wantRead, tt.wantErr)
+			}
+		})
+	}
+	if n, err := new(DoubleArray).ReadFrom(bytes.NewReader(valid)); err != nil || n != int64(len(valid)) {
+		t.Fatalf("ReadFrom() = %v, %v, want %v, nil", n, err, len(valid))
+	}
+}
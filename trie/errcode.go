@@ -0,0 +1,22 @@
+package trie
+
+import (
+	"errors"
+)
+
+var (
+	// ErrInvalidAlphabet 字母表为空或含有重复字符
+	ErrInvalidAlphabet = errors.New("alphabet is empty or has duplicate characters")
+	// ErrOutOfAlphabet 单词含有字母表以外的字符
+	ErrOutOfAlphabet = errors.New("word has characters out of the alphabet")
+	// ErrInvalidPattern 通配符模式语法错误
+	ErrInvalidPattern = errors.New("invalid wildcard pattern")
+	// ErrUnsortedKeys 键不是严格递增的
+	ErrUnsortedKeys = errors.New("keys must be sorted and unique")
+	// ErrValuesLength 值的个数与键的个数不一致
+	ErrValuesLength = errors.New("number of values does not match number of keys")
+	// ErrInvalidData 二进制数据格式错误
+	ErrInvalidData = errors.New("invalid binary data")
+	// ErrInvalidPrefix IP前缀不合法
+	ErrInvalidPrefix = errors.New("invalid ip prefix")
+)
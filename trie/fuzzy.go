@@ -0,0 +1,124 @@
+package trie
+
+import (
+	"sort"
+)
+
+// FuzzyMatch 模糊搜索的结果
+type FuzzyMatch struct {
+	Word string
+	// Distance 与查询词的编辑距离
+	Distance int
+}
+
+// FuzzySearch 返回t中与word的编辑距离（Levenshtein距离，按Unicode字符计算）不超过k的所有单词，
+// 按距离升序排列，距离相同时按字典序
+// 沿前缀树向下时逐字符计算动态规划的一行，一行中的最小值超过k时之后只会更大，整棵子树被剪除
+func FuzzySearch(t ExtendedTrie, word string, k int) []FuzzyMatch {
+	if k < 0 {
+		return nil
+	}
+	var (
+		matches []FuzzyMatch
+		m       = newLevenshteinMatcher(word, k)
+	)
+	if w, ok := t.(walkable); ok {
+		matchTrie(w, "", m, func(word string, _ walkNode) bool {
+			matches = append(matches, FuzzyMatch{Word: word, Distance: m.distance()})
+			return true
+		})
+	} else {
+		// 其他实现只能逐个计算
+		t.Walk("", func(word string) bool {
+			m.rewind(0)
+			for _, r := range word {
+				if !m.step(r) {
+					return true
+				}
+			}
+			if m.accept() {
+				matches = append(matches, FuzzyMatch{Word: word, Distance: m.distance()})
+			}
+			return true
+		})
+	}
+	// 遍历已按字典序，稳定排序即可保证距离相同时按字典序
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Distance < matches[j].Distance })
+	return matches
+}
+
+// levenshteinMatcher 逐字符计算编辑距离的动态规划
+type levenshteinMatcher struct {
+	target []rune
+	k      int
+	// rows[d][j] 已读入的d个字符与target[:j]的编辑距离
+	rows [][]int
+}
+
+var _ runeMatcher = (*levenshteinMatcher)(nil)
+
+func newLevenshteinMatcher(word string, k int) *levenshteinMatcher {
+	var m = &levenshteinMatcher{target: []rune(word), k: k}
+	var row = make([]int, len(m.target)+1)
+	for j := range row {
+		row[j] = j
+	}
+	m.rows = append(m.rows, row)
+	return m
+}
+
+func (m *levenshteinMatcher) step(r rune) bool {
+	var (
+		top  = len(m.rows)
+		prev = m.rows[top-1]
+		cur  []int
+	)
+	// 复用已回退的行
+	if top < cap(m.rows) && m.rows[:top+1][top] != nil {
+		cur = m.rows[:top+1][top]
+	} else {
+		cur = make([]int, len(prev))
+	}
+	cur[0] = prev[0] + 1
+	var rowMin = cur[0]
+	for j := 1; j < len(cur); j++ {
+		var cost = 1
+		if m.target[j-1] == r {
+			cost = 0
+		}
+		// 删除、插入、替换
+		cur[j] = prev[j] + 1
+		if cur[j-1]+1 < cur[j] {
+			cur[j] = cur[j-1] + 1
+		}
+		if prev[j-1]+cost < cur[j] {
+			cur[j] = prev[j-1] + cost
+		}
+		if cur[j] < rowMin {
+			rowMin = cur[j]
+		}
+	}
+	if rowMin > m.k {
+		return false
+	}
+	m.rows = append(m.rows, cur)
+	return true
+}
+
+func (m *levenshteinMatcher) depth() int {
+	return len(m.rows) - 1
+}
+
+func (m *levenshteinMatcher) rewind(depth int) {
+	m.rows = m.rows[:depth+1]
+}
+
+func (m *levenshteinMatcher) accept() bool {
+	return m.distance() <= m.k
+}
+
+// distance 已读入的字符串与target的编辑距离
+func (m *levenshteinMatcher) distance() int {
+	var row = m.rows[len(m.rows)-1]
+	return row[len(row)-1]
+}
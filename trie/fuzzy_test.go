@@ -0,0 +1,50 @@
+package trie
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuzzySearch(t *testing.T) {
+	var words = []string{"book", "books", "cake", "boo", "boon", "cook", "cart", "back"}
+	tests := []struct {
+		word string
+		k    int
+		want []FuzzyMatch
+	}{
+		{word: "book", k: 0, want: []FuzzyMatch{{Word: "book", Distance: 0}}},
+		{
+			word: "book",
+			k:    1,
+			want: []FuzzyMatch{{Word: "book", Distance: 0}, {Word: "boo", Distance: 1}, {Word: "books", Distance: 1}, {Word: "boon", Distance: 1}, {Word: "cook", Distance: 1}},
+		},
+		{word: "bakc", k: 2, want: []FuzzyMatch{{Word: "back", Distance: 2}, {Word: "cake", Distance: 2}}},
+		{word: "zzzz", k: 2, want: nil},
+		{word: "book", k: -1, want: nil},
+	}
+	for _, tt := range allTypes {
+		t.Run(tt.name, func(t *testing.T) {
+			var tr = NewTrie(tt.t)
+			for _, w := range words {
+				tr.Insert(w)
+			}
+			for _, c := range tests {
+				if got := FuzzySearch(tr, c.word, c.k); !reflect.DeepEqual(got, c.want) {
+					t.Errorf("FuzzySearch(%q, %d) = %v, want %v", c.word, c.k, got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFuzzySearch_Unicode(t *testing.T) {
+	// 按字节组织的HashTrie中编辑距离仍按完整字符计算
+	var tr = NewHashTrie()
+	for _, w := range []string{"中国", "中华", "美国", "中国人"} {
+		tr.Insert(w)
+	}
+	var want = []FuzzyMatch{{Word: "中国", Distance: 0}, {Word: "中华", Distance: 1}, {Word: "中国人", Distance: 1}, {Word: "美国", Distance: 1}}
+	if got := FuzzySearch(tr, "中国", 1); !reflect.DeepEqual(got, want) {
+		t.Errorf("FuzzySearch(\"中国\", 1) = %v, want %v", got, want)
+	}
+}
@@ -1,12 +1,21 @@
 package trie
 
+import (
+	"sort"
+)
+
 // HashTrie Hash前缀树
 type HashTrie struct {
-	isWord   bool
+	isWord bool
+	// 以该节点为前缀的单词个数
+	count    int
 	children map[byte]*HashTrie
 }
 
-var _ Trie = (*HashTrie)(nil)
+var (
+	_ ExtendedTrie = (*HashTrie)(nil)
+	_ walkable     = (*HashTrie)(nil)
+)
 
 // NewHashTrie new hashTrie
 func NewHashTrie() *HashTrie {
@@ -15,12 +24,17 @@ func NewHashTrie() *HashTrie {
 
 // Insert 往前缀树中添加一个元素word
 func (h *HashTrie) Insert(word string) {
+	if h.Search(word) {
+		return
+	}
 	var curNode = h
+	curNode.count++
 	for i := 0; i < len(word); i++ {
 		if _, has := curNode.children[word[i]]; !has {
 			curNode.children[word[i]] = NewHashTrie()
 		}
 		curNode = curNode.children[word[i]]
+		curNode.count++
 	}
 	curNode.isWord = true
 }
@@ -32,24 +46,102 @@ func (h *HashTrie) Search(word string) bool {
 }
 
 func (h *HashTrie) search(word string) (*HashTrie, bool) {
+	var curNode = h.find(word)
+	if curNode == nil {
+		return nil, false
+	}
+	return curNode, curNode.isWord
+}
+
+// find 沿prefix向下查找节点，不存在时返回nil
+func (h *HashTrie) find(prefix string) *HashTrie {
 	var curNode = h
-	for i := 0; i < len(word); i++ {
-		if curNode.children[word[i]] == nil {
-			return nil, false
+	for i := 0; i < len(prefix); i++ {
+		if curNode = curNode.children[prefix[i]]; curNode == nil {
+			return nil
 		}
-		curNode = curNode.children[word[i]]
 	}
-	return curNode, curNode.isWord
+	return curNode
 }
 
 // HasPrefix 查询前缀树中是否存在前缀prefix
 func (h *HashTrie) HasPrefix(prefix string) bool {
+	return h.find(prefix) != nil
+}
+
+// Delete 从前缀树中删除元素word，并剪除不再有单词经过的分支，word不存在时返回false
+func (h *HashTrie) Delete(word string) bool {
+	if !h.Search(word) {
+		return false
+	}
 	var curNode = h
-	for i := 0; i < len(prefix); i++ {
-		if curNode.children[prefix[i]] == nil {
+	curNode.count--
+	for i := 0; i < len(word); i++ {
+		var next = curNode.children[word[i]]
+		next.count--
+		if next.count == 0 {
+			delete(curNode.children, word[i])
+			return true
+		}
+		curNode = next
+	}
+	curNode.isWord = false
+	return true
+}
+
+// Len 前缀树中的单词个数
+func (h *HashTrie) Len() int {
+	return h.count
+}
+
+// CountPrefix 以prefix为前缀的单词个数
+func (h *HashTrie) CountPrefix(prefix string) int {
+	if curNode := h.find(prefix); curNode != nil {
+		return curNode.count
+	}
+	return 0
+}
+
+// KeysWithPrefix 按字典序返回以prefix为前缀的至多limit个单词，limit<=0时返回全部
+func (h *HashTrie) KeysWithPrefix(prefix string, limit int) []string {
+	return keysWithPrefix(h, prefix, limit)
+}
+
+// Walk 按字典序遍历以prefix为前缀的单词，fn返回false时停止遍历
+func (h *HashTrie) Walk(prefix string, fn func(word string) bool) {
+	if curNode := h.find(prefix); curNode != nil {
+		walkWords(curNode, prefix, "", false, fn)
+	}
+}
+
+// WalkAfter 按字典序遍历以prefix为前缀且大于after的单词，fn返回false时停止遍历
+func (h *HashTrie) WalkAfter(prefix, after string, fn func(word string) bool) {
+	if curNode := h.find(prefix); curNode != nil {
+		walkWords(curNode, prefix, after, true, fn)
+	}
+}
+
+func (h *HashTrie) isKey() bool {
+	return h.isWord
+}
+
+func (h *HashTrie) eachChild(fn func(label string, child walkNode) bool) bool {
+	var chars = make([]byte, 0, len(h.children))
+	for c := range h.children {
+		chars = append(chars, c)
+	}
+	sort.Slice(chars, func(i, j int) bool { return chars[i] < chars[j] })
+	for _, c := range chars {
+		if !fn(byteLabels[c], h.children[c]) {
 			return false
 		}
-		curNode = curNode.children[prefix[i]]
 	}
 	return true
 }
+
+func (h *HashTrie) prefixNode(prefix string) (walkNode, string) {
+	if curNode := h.find(prefix); curNode != nil {
+		return curNode, prefix
+	}
+	return nil, ""
+}
@@ -5,3 +5,21 @@ type Trie interface {
 	Search(string) bool
 	HasPrefix(string) bool
 }
+
+// ExtendedTrie 在Trie的基础上支持删除、计数与按字典序遍历，本包中的所有前缀树都实现了该接口
+// 单独定义而不是直接扩展Trie，以免破坏外部对Trie的实现
+type ExtendedTrie interface {
+	Trie
+	// Delete 删除单词，单词不存在时返回false
+	Delete(string) bool
+	// Len 单词个数
+	Len() int
+	// CountPrefix 以prefix为前缀的单词个数
+	CountPrefix(string) int
+	// KeysWithPrefix 按字典序返回以prefix为前缀的至多limit个单词，limit<=0时返回全部
+	KeysWithPrefix(prefix string, limit int) []string
+	// Walk 按字典序遍历以prefix为前缀的单词，fn返回false时停止遍历
+	Walk(prefix string, fn func(word string) bool)
+	// WalkAfter 按字典序遍历以prefix为前缀且大于after的单词，用于从上次的位置继续遍历
+	WalkAfter(prefix, after string, fn func(word string) bool)
+}
@@ -0,0 +1,232 @@
+package trie
+
+import (
+	"net"
+)
+
+// IPTrie 以IPv4/IPv6 CIDR前缀为键的二进制前缀树，用于路由表等最长前缀匹配的场景
+// 第i层按地址的第i位分叉，长度为n的前缀存放在第n层的节点上，IPv4与IPv6分别使用一棵树
+// IPv4映射的IPv6地址（如::ffff:10.0.0.1）按IPv4处理，长度不小于96的映射前缀（如::ffff:10.0.0.0/104）
+// 转换为对应的IPv4前缀（10.0.0.0/8）存放，更短的IPv6前缀（如::/0）不覆盖IPv4地址
+type IPTrie struct {
+	v4   *ipNode
+	v6   *ipNode
+	size int
+}
+
+type ipNode struct {
+	children [2]*ipNode
+	hasValue bool
+	value    interface{}
+	// 子树中前缀的个数
+	count int
+}
+
+// IPEntry 一条前缀及其值
+type IPEntry struct {
+	Prefix *net.IPNet
+	Value  interface{}
+}
+
+// NewIPTrie new ipTrie
+func NewIPTrie() *IPTrie {
+	return &IPTrie{v4: new(ipNode), v6: new(ipNode)}
+}
+
+// Len 前缀的个数
+func (t *IPTrie) Len() int {
+	return t.size
+}
+
+// parsePrefix 前缀的网络地址与长度，以及所属的树
+func (t *IPTrie) parsePrefix(prefix *net.IPNet) (net.IP, int, *ipNode, error) {
+	if prefix == nil {
+		return nil, 0, nil, ErrInvalidPrefix
+	}
+	var ones, bits = prefix.Mask.Size()
+	switch bits {
+	case 8 * net.IPv4len:
+		if ip := prefix.IP.To4(); ip != nil {
+			return ip.Mask(prefix.Mask), ones, t.v4, nil
+		}
+	case 8 * net.IPv6len:
+		if ip := prefix.IP.To16(); ip != nil {
+			ip = ip.Mask(prefix.Mask)
+			if ip4 := ip.To4(); ip4 != nil && ones >= 8*(net.IPv6len-net.IPv4len) {
+				// 与parseIP一致，IPv4映射前缀存放在IPv4树中
+				return ip4, ones - 8*(net.IPv6len-net.IPv4len), t.v4, nil
+			}
+			return ip, ones, t.v6, nil
+		}
+	}
+	// 掩码不连续或与地址长度不符
+	return nil, 0, nil, ErrInvalidPrefix
+}
+
+// parseIP 地址的字节形式以及所属的树，IPv4映射地址属于IPv4树
+func (t *IPTrie) parseIP(ip net.IP) (net.IP, *ipNode) {
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4, t.v4
+	}
+	if ip16 := ip.To16(); ip16 != nil {
+		return ip16, t.v6
+	}
+	return nil, nil
+}
+
+// ipBit 地址的第i位
+func ipBit(ip net.IP, i int) int {
+	return int(ip[i/8]>>(7-uint(i)%8)) & 1
+}
+
+// ipPrefix 由地址的前ones位构造前缀
+func ipPrefix(ip net.IP, ones int) *net.IPNet {
+	var mask = net.CIDRMask(ones, 8*len(ip))
+	return &net.IPNet{IP: ip.Mask(mask), Mask: mask}
+}
+
+// find 前缀对应的节点，不存在时返回nil
+func (t *IPTrie) find(prefix *net.IPNet) *ipNode {
+	var ip, ones, curNode, err = t.parsePrefix(prefix)
+	if err != nil {
+		return nil
+	}
+	for i := 0; i < ones && curNode != nil; i++ {
+		curNode = curNode.children[ipBit(ip, i)]
+	}
+	return curNode
+}
+
+// Insert 插入前缀及其值，已存在时覆盖，前缀不合法时返回ErrInvalidPrefix
+func (t *IPTrie) Insert(prefix *net.IPNet, value interface{}) error {
+	var ip, ones, curNode, err = t.parsePrefix(prefix)
+	if err != nil {
+		return err
+	}
+	if n := t.find(prefix); n != nil && n.hasValue {
+		n.value = value
+		return nil
+	}
+	curNode.count++
+	for i := 0; i < ones; i++ {
+		var b = ipBit(ip, i)
+		if curNode.children[b] == nil {
+			curNode.children[b] = new(ipNode)
+		}
+		curNode = curNode.children[b]
+		curNode.count++
+	}
+	curNode.hasValue, curNode.value = true, value
+	t.size++
+	return nil
+}
+
+// Delete 删除前缀并剪除不再有前缀的分支，前缀不存在时返回false
+func (t *IPTrie) Delete(prefix *net.IPNet) bool {
+	if n := t.find(prefix); n == nil || !n.hasValue {
+		return false
+	}
+	var ip, ones, curNode, _ = t.parsePrefix(prefix)
+	curNode.count--
+	for i := 0; i < ones; i++ {
+		var (
+			b    = ipBit(ip, i)
+			next = curNode.children[b]
+		)
+		next.count--
+		if next.count == 0 {
+			curNode.children[b] = nil
+			t.size--
+			return true
+		}
+		curNode = next
+	}
+	curNode.hasValue, curNode.value = false, nil
+	t.size--
+	return true
+}
+
+// Get 精确查找前缀的值
+func (t *IPTrie) Get(prefix *net.IPNet) (interface{}, bool) {
+	if n := t.find(prefix); n != nil && n.hasValue {
+		return n.value, true
+	}
+	return nil, false
+}
+
+// Lookup 最长前缀匹配：包含ip的最长前缀及其值
+func (t *IPTrie) Lookup(ip net.IP) (*net.IPNet, interface{}, bool) {
+	var addr, curNode = t.parseIP(ip)
+	if curNode == nil {
+		return nil, nil, false
+	}
+	var (
+		best      *ipNode
+		bestDepth int
+	)
+	for i := 0; curNode != nil; i++ {
+		if curNode.hasValue {
+			best, bestDepth = curNode, i
+		}
+		if i == 8*len(addr) {
+			break
+		}
+		curNode = curNode.children[ipBit(addr, i)]
+	}
+	if best == nil {
+		return nil, nil, false
+	}
+	return ipPrefix(addr, bestDepth), best.value, true
+}
+
+// Covering 包含prefix的所有前缀（包括prefix本身），从短到长
+func (t *IPTrie) Covering(prefix *net.IPNet) []IPEntry {
+	var ip, ones, curNode, err = t.parsePrefix(prefix)
+	if err != nil {
+		return nil
+	}
+	var entries []IPEntry
+	for i := 0; curNode != nil; i++ {
+		if curNode.hasValue {
+			entries = append(entries, IPEntry{Prefix: ipPrefix(ip, i), Value: curNode.value})
+		}
+		if i == ones {
+			break
+		}
+		curNode = curNode.children[ipBit(ip, i)]
+	}
+	return entries
+}
+
+// Covered 被prefix包含的所有前缀（包括prefix本身），按地址升序，地址相同时短的在前
+func (t *IPTrie) Covered(prefix *net.IPNet) []IPEntry {
+	var ip, ones, _, err = t.parsePrefix(prefix)
+	if err != nil {
+		return nil
+	}
+	var (
+		entries []IPEntry
+		addr    = make(net.IP, len(ip))
+		dfs     func(n *ipNode, depth int)
+	)
+	copy(addr, ip)
+	dfs = func(n *ipNode, depth int) {
+		if n.hasValue {
+			entries = append(entries, IPEntry{Prefix: ipPrefix(addr, depth), Value: n.value})
+		}
+		for b, child := range n.children {
+			if child == nil {
+				continue
+			}
+			if b == 1 {
+				addr[depth/8] |= 1 << (7 - uint(depth)%8)
+			}
+			dfs(child, depth+1)
+			addr[depth/8] &^= 1 << (7 - uint(depth)%8)
+		}
+	}
+	if n := t.find(prefix); n != nil {
+		dfs(n, ones)
+	}
+	return entries
+}
@@ -0,0 +1,125 @@
+package trie
+
+import (
+	"net"
+	"testing"
+)
+
+func mustCIDR(s string) *net.IPNet {
+	var _, prefix, err = net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return prefix
+}
+
+func TestIPTrie_Lookup(t *testing.T) {
+	var tr = NewIPTrie()
+	for _, s := range []string{"0.0.0.0/0", "10.0.0.0/8", "10.1.0.0/16", "10.1.2.0/24", "192.168.1.0/24", "2001:db8::/32", "2001:db8:1::/48"} {
+		if err := tr.Insert(mustCIDR(s), s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tr.Insert(&net.IPNet{IP: net.ParseIP("10.0.0.0"), Mask: net.IPMask{255, 0, 255, 0}}, nil); err != ErrInvalidPrefix {
+		t.Fatalf("Insert() error = %v, want %v", err, ErrInvalidPrefix)
+	}
+	tests := []struct {
+		ip   string
+		want string
+		ok   bool
+	}{
+		{ip: "10.1.2.3", want: "10.1.2.0/24", ok: true},
+		{ip: "10.1.3.3", want: "10.1.0.0/16", ok: true},
+		{ip: "10.200.0.1", want: "10.0.0.0/8", ok: true},
+		{ip: "8.8.8.8", want: "0.0.0.0/0", ok: true},
+		{ip: "2001:db8:1::1", want: "2001:db8:1::/48", ok: true},
+		{ip: "2001:db8:2::1", want: "2001:db8::/32", ok: true},
+		{ip: "2001:db9::1", ok: false},
+	}
+	for _, tt := range tests {
+		var prefix, value, ok = tr.Lookup(net.ParseIP(tt.ip))
+		if ok != tt.ok || (ok && (prefix.String() != tt.want || value != tt.want)) {
+			t.Errorf("Lookup(%s) = %v, %v, %v, want %v", tt.ip, prefix, value, ok, tt.want)
+		}
+	}
+	if !tr.Delete(mustCIDR("10.1.0.0/16")) || tr.Delete(mustCIDR("10.1.0.0/16")) {
+		t.Fatalf("Delete(10.1.0.0/16) mismatch")
+	}
+	if prefix, _, _ := tr.Lookup(net.ParseIP("10.1.3.3")); prefix.String() != "10.0.0.0/8" {
+		t.Errorf("Lookup(10.1.3.3) after Delete = %v, want 10.0.0.0/8", prefix)
+	}
+	if v, ok := tr.Get(mustCIDR("10.1.2.0/24")); !ok || v != "10.1.2.0/24" || tr.Len() != 6 {
+		t.Errorf("Get(10.1.2.0/24) = %v, %v, Len() = %v", v, ok, tr.Len())
+	}
+}
+
+func TestIPTrie_CoveringAndCovered(t *testing.T) {
+	var tr = NewIPTrie()
+	for _, s := range []string{"10.0.0.0/8", "10.1.0.0/16", "10.1.2.0/24", "10.1.128.0/17", "10.2.0.0/16", "11.0.0.0/8"} {
+		if err := tr.Insert(mustCIDR(s), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var prefixes = func(entries []IPEntry) []string {
+		var ans []string
+		for _, e := range entries {
+			ans = append(ans, e.Prefix.String())
+		}
+		return ans
+	}
+	tests := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{name: "covering", got: prefixes(tr.Covering(mustCIDR("10.1.2.128/25"))), want: []string{"10.0.0.0/8", "10.1.0.0/16", "10.1.2.0/24"}},
+		{name: "covering self", got: prefixes(tr.Covering(mustCIDR("10.1.0.0/16"))), want: []string{"10.0.0.0/8", "10.1.0.0/16"}},
+		{name: "covered", got: prefixes(tr.Covered(mustCIDR("10.1.0.0/16"))), want: []string{"10.1.0.0/16", "10.1.2.0/24", "10.1.128.0/17"}},
+		{name: "covered all", got: prefixes(tr.Covered(mustCIDR("0.0.0.0/0"))), want: []string{"10.0.0.0/8", "10.1.0.0/16", "10.1.2.0/24", "10.1.128.0/17", "10.2.0.0/16", "11.0.0.0/8"}},
+		{name: "covered none", got: prefixes(tr.Covered(mustCIDR("12.0.0.0/8"))), want: nil},
+	}
+	for _, tt := range tests {
+		if len(tt.got) != len(tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+			continue
+		}
+		for i := range tt.got {
+			if tt.got[i] != tt.want[i] {
+				t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestIPTrie_IPv4Mapped(t *testing.T) {
+	var tr = NewIPTrie()
+	for _, s := range []string{"::ffff:10.0.0.0/104", "::/0"} {
+		if err := tr.Insert(mustCIDR(s), s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		ip   string
+		want string
+		ok   bool
+	}{
+		{ip: "::ffff:10.0.0.1", want: "10.0.0.0/8", ok: true},
+		{ip: "10.0.0.1", want: "10.0.0.0/8", ok: true},
+		{ip: "11.0.0.1", ok: false},
+		{ip: "2001:db8::1", want: "::/0", ok: true},
+	}
+	for _, tt := range tests {
+		var prefix, _, ok = tr.Lookup(net.ParseIP(tt.ip))
+		if ok != tt.ok || (ok && prefix.String() != tt.want) {
+			t.Errorf("Lookup(%s) = %v, %v, want %v, %v", tt.ip, prefix, ok, tt.want, tt.ok)
+		}
+	}
+	// 映射前缀与对应的IPv4前缀是同一个键
+	if v, ok := tr.Get(mustCIDR("10.0.0.0/8")); !ok || v != "::ffff:10.0.0.0/104" {
+		t.Errorf("Get(10.0.0.0/8) = %v, %v", v, ok)
+	}
+	if !tr.Delete(mustCIDR("10.0.0.0/8")) || tr.Len() != 1 {
+		t.Errorf("Delete(10.0.0.0/8) mismatch, Len() = %v", tr.Len())
+	}
+}
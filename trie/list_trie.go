@@ -1,13 +1,22 @@
 package trie
 
+import (
+	"sort"
+)
+
 // ListTrie 列表前缀树
 type ListTrie struct {
-	isWord   bool
-	char     byte
+	isWord bool
+	char   byte
+	// 以该节点为前缀的单词个数
+	count    int
 	children []*ListTrie
 }
 
-var _ Trie = (*ListTrie)(nil)
+var (
+	_ ExtendedTrie = (*ListTrie)(nil)
+	_ walkable     = (*ListTrie)(nil)
+)
 
 // NewListTrie new listTrie
 func NewListTrie() *ListTrie {
@@ -16,15 +25,20 @@ func NewListTrie() *ListTrie {
 
 // Insert 往前缀树中添加一个元素word
 func (a *ListTrie) Insert(word string) {
+	if a.Search(word) {
+		return
+	}
 	var (
 		curNode = a
 		tNode   *ListTrie
 	)
+	curNode.count++
 	for i := 0; i < len(word); i++ {
 		if tNode = curNode.find(word[i]); tNode == nil {
 			curNode.children = append(curNode.children, &ListTrie{char: word[i]})
 		}
 		curNode = curNode.find(word[i])
+		curNode.count++
 	}
 	curNode.isWord = true
 }
@@ -38,6 +52,18 @@ func (a *ListTrie) find(c byte) *ListTrie {
 	return nil
 }
 
+// remove 删除字符为c的子节点
+func (a *ListTrie) remove(c byte) {
+	for i := 0; i < len(a.children); i++ {
+		if a.children[i].char == c {
+			copy(a.children[i:], a.children[i+1:])
+			a.children[len(a.children)-1] = nil
+			a.children = a.children[:len(a.children)-1]
+			return
+		}
+	}
+}
+
 // Search 查找前缀树中是否元素word
 func (a *ListTrie) Search(word string) bool {
 	var _, has = a.search(word)
@@ -45,24 +71,101 @@ func (a *ListTrie) Search(word string) bool {
 }
 
 func (a *ListTrie) search(word string) (*ListTrie, bool) {
+	var curNode = a.findPrefix(word)
+	if curNode == nil {
+		return nil, false
+	}
+	return curNode, curNode.isWord
+}
+
+// findPrefix 沿prefix向下查找节点，不存在时返回nil
+func (a *ListTrie) findPrefix(prefix string) *ListTrie {
 	var curNode = a
-	for i := 0; i < len(word); i++ {
-		curNode = curNode.find(word[i])
+	for i := 0; i < len(prefix); i++ {
+		curNode = curNode.find(prefix[i])
 		if curNode == nil {
-			return nil, false
+			return nil
 		}
 	}
-	return curNode, curNode.isWord
+	return curNode
 }
 
 // HasPrefix 查询前缀树中是否存在前缀prefix
 func (a *ListTrie) HasPrefix(prefix string) bool {
+	return a.findPrefix(prefix) != nil
+}
+
+// Delete 从前缀树中删除元素word，并剪除不再有单词经过的分支，word不存在时返回false
+func (a *ListTrie) Delete(word string) bool {
+	if !a.Search(word) {
+		return false
+	}
 	var curNode = a
-	for i := 0; i < len(prefix); i++ {
-		curNode = curNode.find(prefix[i])
-		if curNode == nil {
+	curNode.count--
+	for i := 0; i < len(word); i++ {
+		var next = curNode.find(word[i])
+		next.count--
+		if next.count == 0 {
+			curNode.remove(word[i])
+			return true
+		}
+		curNode = next
+	}
+	curNode.isWord = false
+	return true
+}
+
+// Len 前缀树中的单词个数
+func (a *ListTrie) Len() int {
+	return a.count
+}
+
+// CountPrefix 以prefix为前缀的单词个数
+func (a *ListTrie) CountPrefix(prefix string) int {
+	if curNode := a.findPrefix(prefix); curNode != nil {
+		return curNode.count
+	}
+	return 0
+}
+
+// KeysWithPrefix 按字典序返回以prefix为前缀的至多limit个单词，limit<=0时返回全部
+func (a *ListTrie) KeysWithPrefix(prefix string, limit int) []string {
+	return keysWithPrefix(a, prefix, limit)
+}
+
+// Walk 按字典序遍历以prefix为前缀的单词，fn返回false时停止遍历
+func (a *ListTrie) Walk(prefix string, fn func(word string) bool) {
+	if curNode := a.findPrefix(prefix); curNode != nil {
+		walkWords(curNode, prefix, "", false, fn)
+	}
+}
+
+// WalkAfter 按字典序遍历以prefix为前缀且大于after的单词，fn返回false时停止遍历
+func (a *ListTrie) WalkAfter(prefix, after string, fn func(word string) bool) {
+	if curNode := a.findPrefix(prefix); curNode != nil {
+		walkWords(curNode, prefix, after, true, fn)
+	}
+}
+
+func (a *ListTrie) isKey() bool {
+	return a.isWord
+}
+
+func (a *ListTrie) eachChild(fn func(label string, child walkNode) bool) bool {
+	var children = make([]*ListTrie, len(a.children))
+	copy(children, a.children)
+	sort.Slice(children, func(i, j int) bool { return children[i].char < children[j].char })
+	for _, child := range children {
+		if !fn(byteLabels[child.char], child) {
 			return false
 		}
 	}
 	return true
 }
+
+func (a *ListTrie) prefixNode(prefix string) (walkNode, string) {
+	if curNode := a.findPrefix(prefix); curNode != nil {
+		return curNode, prefix
+	}
+	return nil, ""
+}
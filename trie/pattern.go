@@ -0,0 +1,283 @@
+package trie
+
+import (
+	"strings"
+)
+
+// tokenKind 模式中单元的类型
+type tokenKind uint8
+
+const (
+	// tokenLiteral 普通字符
+	tokenLiteral tokenKind = iota
+	// tokenAny '?' 任意一个字符
+	tokenAny
+	// tokenStar '*' 任意长度的字符串
+	tokenStar
+	// tokenClass 字符类
+	tokenClass
+)
+
+// runeRange 字符闭区间[lo, hi]
+type runeRange struct {
+	lo, hi rune
+}
+
+type patternToken struct {
+	kind tokenKind
+	// tokenLiteral的字符
+	char rune
+	// tokenClass是否取反
+	negate bool
+	// tokenClass包含的字符区间
+	ranges []runeRange
+}
+
+// match 是否匹配字符r，tokenStar不消耗字符，由调用方处理
+func (tok *patternToken) match(r rune) bool {
+	switch tok.kind {
+	case tokenLiteral:
+		return tok.char == r
+	case tokenAny:
+		return true
+	case tokenClass:
+		for _, rg := range tok.ranges {
+			if rg.lo <= r && r <= rg.hi {
+				return !tok.negate
+			}
+		}
+		return tok.negate
+	}
+	return false
+}
+
+// Pattern 通配符模式
+// '?'匹配任意一个字符，'*'匹配任意长度（可以为空）的字符串，[abc]、[a-z]、[^0-9]匹配字符类，'\'转义下一个字符
+// 这里的字符均指完整的Unicode字符，因此'?'在按字节组织的前缀树中也能匹配一个汉字
+type Pattern struct {
+	source string
+	tokens []patternToken
+	// 模式开头的普通字符组成的前缀，在前缀树上可以直接定位
+	prefix string
+}
+
+// CompilePattern 解析通配符模式，语法错误时返回ErrInvalidPattern
+func CompilePattern(pattern string) (*Pattern, error) {
+	var (
+		p     = &Pattern{source: pattern}
+		chars = []rune(pattern)
+	)
+	for i := 0; i < len(chars); i++ {
+		switch chars[i] {
+		case '?':
+			p.tokens = append(p.tokens, patternToken{kind: tokenAny})
+		case '*':
+			// 连续的'*'等价于一个
+			if n := len(p.tokens); n == 0 || p.tokens[n-1].kind != tokenStar {
+				p.tokens = append(p.tokens, patternToken{kind: tokenStar})
+			}
+		case '[':
+			var tok, next, err = parseClass(chars, i+1)
+			if err != nil {
+				return nil, err
+			}
+			p.tokens = append(p.tokens, tok)
+			i = next
+		case '\\':
+			if i+1 == len(chars) {
+				return nil, ErrInvalidPattern
+			}
+			i++
+			p.tokens = append(p.tokens, patternToken{kind: tokenLiteral, char: chars[i]})
+		default:
+			p.tokens = append(p.tokens, patternToken{kind: tokenLiteral, char: chars[i]})
+		}
+	}
+	var prefix strings.Builder
+	for _, tok := range p.tokens {
+		if tok.kind != tokenLiteral {
+			break
+		}
+		prefix.WriteRune(tok.char)
+	}
+	p.prefix = prefix.String()
+	return p, nil
+}
+
+// parseClass 解析从chars[i]开始、'['之后的字符类，返回字符类及结尾']'的下标
+// 紧跟在'['或'[^'之后的']'视为普通字符
+func parseClass(chars []rune, i int) (patternToken, int, error) {
+	var tok = patternToken{kind: tokenClass}
+	if i < len(chars) && chars[i] == '^' {
+		tok.negate = true
+		i++
+	}
+	for start := i; i < len(chars); i++ {
+		if chars[i] == ']' && i > start {
+			return tok, i, nil
+		}
+		var lo = chars[i]
+		if lo == '\\' {
+			if i+1 == len(chars) {
+				break
+			}
+			i++
+			lo = chars[i]
+		}
+		var hi = lo
+		if i+2 < len(chars) && chars[i+1] == '-' && chars[i+2] != ']' {
+			i += 2
+			if hi = chars[i]; hi == '\\' {
+				if i+1 == len(chars) {
+					break
+				}
+				i++
+				hi = chars[i]
+			}
+			if hi < lo {
+				return tok, 0, ErrInvalidPattern
+			}
+		}
+		tok.ranges = append(tok.ranges, runeRange{lo: lo, hi: hi})
+	}
+	// 缺少结尾的']'
+	return tok, 0, ErrInvalidPattern
+}
+
+// String 模式的原始字符串
+func (p *Pattern) String() string {
+	return p.source
+}
+
+// MatchString word是否完整匹配模式
+func (p *Pattern) MatchString(word string) bool {
+	var m = newPatternMatcher(p)
+	for _, r := range word {
+		if !m.step(r) {
+			return false
+		}
+	}
+	return m.accept()
+}
+
+// Exists t中是否存在匹配模式的单词
+func (p *Pattern) Exists(t ExtendedTrie) bool {
+	return len(p.FindAll(t, 1)) > 0
+}
+
+// FindAll 按字典序返回t中匹配模式的至多limit个单词，limit<=0时返回全部
+// 遍历时逐字符推进匹配状态，已不可能匹配的子树会被剪除，开头的普通字符直接用于定位前缀
+func (p *Pattern) FindAll(t ExtendedTrie, limit int) []string {
+	var (
+		words []string
+		fn    = func(word string) bool {
+			words = append(words, word)
+			return limit <= 0 || len(words) < limit
+		}
+	)
+	if w, ok := t.(walkable); ok {
+		matchTrie(w, p.prefix, newPatternMatcher(p), func(word string, _ walkNode) bool {
+			return fn(word)
+		})
+		return words
+	}
+	// 其他实现只能逐个检查以prefix为前缀的单词
+	t.Walk(p.prefix, func(word string) bool {
+		return !p.MatchString(word) || fn(word)
+	})
+	return words
+}
+
+// Match t中是否存在匹配通配符模式pattern的单词
+func Match(t ExtendedTrie, pattern string) (bool, error) {
+	var p, err = CompilePattern(pattern)
+	if err != nil {
+		return false, err
+	}
+	return p.Exists(t), nil
+}
+
+// MatchAll 按字典序返回t中所有匹配通配符模式pattern的单词
+func MatchAll(t ExtendedTrie, pattern string) ([]string, error) {
+	var p, err = CompilePattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return p.FindAll(t, 0), nil
+}
+
+// patternMatcher 模式对应的非确定有限自动机，状态i表示已匹配tokens[:i]
+type patternMatcher struct {
+	tokens []patternToken
+	// stack[d] 读入d个字符后的状态集合
+	stack [][]int
+	// 状态去重标记
+	seen []int
+	gen  int
+}
+
+var _ runeMatcher = (*patternMatcher)(nil)
+
+func newPatternMatcher(p *Pattern) *patternMatcher {
+	var m = &patternMatcher{tokens: p.tokens, seen: make([]int, len(p.tokens)+1)}
+	m.gen++
+	m.stack = append(m.stack, m.add(nil, 0))
+	return m
+}
+
+// add 将状态i加入集合，'*'可以匹配空串，因此同时加入其后的状态
+func (m *patternMatcher) add(states []int, i int) []int {
+	if m.seen[i] == m.gen {
+		return states
+	}
+	m.seen[i] = m.gen
+	states = append(states, i)
+	if i < len(m.tokens) && m.tokens[i].kind == tokenStar {
+		states = m.add(states, i+1)
+	}
+	return states
+}
+
+func (m *patternMatcher) step(r rune) bool {
+	var (
+		top  = len(m.stack)
+		next []int
+	)
+	// 复用已回退的状态集合
+	if top < cap(m.stack) {
+		next = m.stack[:top+1][top][:0]
+	}
+	m.gen++
+	for _, i := range m.stack[top-1] {
+		if i == len(m.tokens) {
+			continue
+		}
+		if m.tokens[i].kind == tokenStar {
+			next = m.add(next, i)
+		} else if m.tokens[i].match(r) {
+			next = m.add(next, i+1)
+		}
+	}
+	if len(next) == 0 {
+		return false
+	}
+	m.stack = append(m.stack, next)
+	return true
+}
+
+func (m *patternMatcher) depth() int {
+	return len(m.stack) - 1
+}
+
+func (m *patternMatcher) rewind(depth int) {
+	m.stack = m.stack[:depth+1]
+}
+
+func (m *patternMatcher) accept() bool {
+	for _, i := range m.stack[len(m.stack)-1] {
+		if i == len(m.tokens) {
+			return true
+		}
+	}
+	return false
+}
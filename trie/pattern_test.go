@@ -0,0 +1,107 @@
+package trie
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompilePattern(t *testing.T) {
+	for _, pattern := range []string{"[abc", "a\\", "[z-a]", "[]", "[^"} {
+		if _, err := CompilePattern(pattern); err != ErrInvalidPattern {
+			t.Errorf("CompilePattern(%q) error = %v, want %v", pattern, err, ErrInvalidPattern)
+		}
+	}
+}
+
+func TestPattern_MatchString(t *testing.T) {
+	tests := []struct {
+		pattern string
+		word    string
+		want    bool
+	}{
+		{pattern: "abc", word: "abc", want: true},
+		{pattern: "abc", word: "abcd", want: false},
+		{pattern: "a?c", word: "abc", want: true},
+		{pattern: "a?c", word: "ac", want: false},
+		{pattern: "a*", word: "a", want: true},
+		{pattern: "a*c", word: "abbbc", want: true},
+		{pattern: "*b*", word: "abc", want: true},
+		{pattern: "*b*", word: "acd", want: false},
+		{pattern: "[a-c]x", word: "bx", want: true},
+		{pattern: "[a-c]x", word: "dx", want: false},
+		{pattern: "[^0-9]", word: "a", want: true},
+		{pattern: "[^0-9]", word: "5", want: false},
+		{pattern: "[]a]", word: "]", want: true},
+		{pattern: "a\\*", word: "a*", want: true},
+		{pattern: "a\\*", word: "ab", want: false},
+		{pattern: "中?", word: "中国", want: true},
+		{pattern: "**", word: "", want: true},
+	}
+	for _, tt := range tests {
+		var p, err = CompilePattern(tt.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := p.MatchString(tt.word); got != tt.want {
+			t.Errorf("%q.MatchString(%q) = %v, want %v", tt.pattern, tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestPattern_FindAll(t *testing.T) {
+	var words = []string{"bad", "bed", "bid", "bead", "dad", "mad", "pad", "b", "a"}
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{pattern: "?ad", want: []string{"bad", "dad", "mad", "pad"}},
+		{pattern: "b?d", want: []string{"bad", "bed", "bid"}},
+		{pattern: "b*d", want: []string{"bad", "bead", "bed", "bid"}},
+		{pattern: "[bd]a?", want: []string{"bad", "dad"}},
+		{pattern: "*", want: []string{"a", "b", "bad", "bead", "bed", "bid", "dad", "mad", "pad"}},
+		{pattern: "?", want: []string{"a", "b"}},
+		{pattern: "x*", want: nil},
+	}
+	for _, tt := range allTypes {
+		t.Run(tt.name, func(t *testing.T) {
+			var tr = NewTrie(tt.t)
+			for _, w := range words {
+				tr.Insert(w)
+			}
+			for _, c := range tests {
+				got, err := MatchAll(tr, c.pattern)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(got, c.want) {
+					t.Errorf("MatchAll(%q) = %v, want %v", c.pattern, got, c.want)
+				}
+			}
+			if ok, _ := Match(tr, "b??d"); !ok {
+				t.Errorf("Match(\"b??d\") = false, want true")
+			}
+			if ok, _ := Match(tr, "b???d"); ok {
+				t.Errorf("Match(\"b???d\") = true, want false")
+			}
+		})
+	}
+}
+
+func TestPattern_FindAllUnicode(t *testing.T) {
+	// 按字节组织的HashTrie中'?'同样匹配一个完整的汉字
+	for _, tr := range []ExtendedTrie{NewHashTrie(), NewRuneTrie()} {
+		for _, w := range []string{"中国", "中华", "中华人民", "美国"} {
+			tr.Insert(w)
+		}
+		got, err := MatchAll(tr, "?国")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"中国", "美国"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("MatchAll(\"?国\") = %v, want %v", got, want)
+		}
+		if got, _ = MatchAll(tr, "中[华]*"); !reflect.DeepEqual(got, []string{"中华", "中华人民"}) {
+			t.Errorf("MatchAll(\"中[华]*\") = %v", got)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package trie
+
+import (
+	"sort"
+	"strings"
+)
+
+// RadixTree 基数树（压缩前缀树）
+// 只有一个子节点且不是单词结尾的节点会与子节点合并，边上存放字符串，适合URL、文件路径等较长且前缀重复的键
+type RadixTree struct {
+	root *radixNode
+}
+
+type radixNode struct {
+	// 从父节点到该节点的边上的字符串
+	label  string
+	isWord bool
+	value  interface{}
+	// 以该节点为前缀的单词个数
+	count int
+	// 按边的首字节升序排列，首字节互不相同
+	children []*radixNode
+}
+
+var (
+	_ ExtendedTrie = (*RadixTree)(nil)
+	_ walkable     = (*RadixTree)(nil)
+)
+
+// NewRadixTree new radixTree
+func NewRadixTree() *RadixTree {
+	return &RadixTree{root: new(radixNode)}
+}
+
+// child 首字节为c的子节点下标，不存在时返回应插入的位置与false
+func (n *radixNode) child(c byte) (int, bool) {
+	var i = sort.Search(len(n.children), func(i int) bool { return n.children[i].label[0] >= c })
+	return i, i < len(n.children) && n.children[i].label[0] == c
+}
+
+// merge 将唯一的子节点合并到n中
+func (n *radixNode) merge() {
+	var child = n.children[0]
+	n.label += child.label
+	n.isWord, n.value, n.children = child.isWord, child.value, child.children
+}
+
+func (n *radixNode) isKey() bool {
+	return n.isWord
+}
+
+func (n *radixNode) eachChild(fn func(label string, child walkNode) bool) bool {
+	for _, child := range n.children {
+		if !fn(child.label, child) {
+			return false
+		}
+	}
+	return true
+}
+
+// commonPrefixLen a与b的最长公共前缀长度
+func commonPrefixLen(a, b string) int {
+	var i int
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// find 查找key对应的节点，不存在时返回nil
+func (t *RadixTree) find(key string) *radixNode {
+	var curNode = t.root
+	for len(key) > 0 {
+		var i, has = curNode.child(key[0])
+		if !has || !strings.HasPrefix(key, curNode.children[i].label) {
+			return nil
+		}
+		curNode = curNode.children[i]
+		key = key[len(curNode.label):]
+	}
+	return curNode
+}
+
+// prefixNode prefix可能结束在某条边的中间，此时返回该边指向的节点以及补全这条边后的字符串
+func (t *RadixTree) prefixNode(prefix string) (walkNode, string) {
+	var (
+		curNode = t.root
+		key     = prefix
+	)
+	for len(key) > 0 {
+		var i, has = curNode.child(key[0])
+		if !has {
+			return nil, ""
+		}
+		var child = curNode.children[i]
+		if len(key) < len(child.label) {
+			if !strings.HasPrefix(child.label, key) {
+				return nil, ""
+			}
+			return child, prefix + child.label[len(key):]
+		}
+		if !strings.HasPrefix(key, child.label) {
+			return nil, ""
+		}
+		curNode = child
+		key = key[len(child.label):]
+	}
+	return curNode, prefix
+}
+
+// Insert 往基数树中添加一个元素word，已存在时不修改其值
+func (t *RadixTree) Insert(word string) {
+	if !t.Search(word) {
+		t.Put(word, nil)
+	}
+}
+
+// Put 设置键key的值为value，key不存在时插入，必要时分裂边
+func (t *RadixTree) Put(key string, value interface{}) {
+	if curNode := t.find(key); curNode != nil && curNode.isWord {
+		curNode.value = value
+		return
+	}
+	var curNode = t.root
+	curNode.count++
+	for len(key) > 0 {
+		var i, has = curNode.child(key[0])
+		if !has {
+			var leaf = &radixNode{label: key, isWord: true, value: value, count: 1}
+			curNode.children = append(curNode.children, nil)
+			copy(curNode.children[i+1:], curNode.children[i:])
+			curNode.children[i] = leaf
+			return
+		}
+		var (
+			child = curNode.children[i]
+			l     = commonPrefixLen(key, child.label)
+		)
+		if l == len(child.label) {
+			child.count++
+			curNode, key = child, key[l:]
+			continue
+		}
+		// 分裂边：公共部分成为新的中间节点
+		var mid = &radixNode{label: child.label[:l], count: child.count + 1, children: []*radixNode{child}}
+		child.label = child.label[l:]
+		curNode.children[i] = mid
+		curNode, key = mid, key[l:]
+	}
+	curNode.isWord, curNode.value = true, value
+}
+
+// Get 键key对应的值
+func (t *RadixTree) Get(key string) (interface{}, bool) {
+	if curNode := t.find(key); curNode != nil && curNode.isWord {
+		return curNode.value, true
+	}
+	return nil, false
+}
+
+// Search 查找基数树中是否元素word
+func (t *RadixTree) Search(word string) bool {
+	var curNode = t.find(word)
+	return curNode != nil && curNode.isWord
+}
+
+// HasPrefix 查询基数树中是否存在前缀prefix
+func (t *RadixTree) HasPrefix(prefix string) bool {
+	var n, _ = t.prefixNode(prefix)
+	return n != nil
+}
+
+// Delete 删除元素word，剪除空的叶子并合并只剩一个子节点的节点，word不存在时返回false
+func (t *RadixTree) Delete(word string) bool {
+	if !t.Search(word) {
+		return false
+	}
+	var (
+		parent  *radixNode
+		curNode = t.root
+		key     = word
+	)
+	curNode.count--
+	for len(key) > 0 {
+		var i, _ = curNode.child(key[0])
+		parent, curNode = curNode, curNode.children[i]
+		curNode.count--
+		key = key[len(curNode.label):]
+	}
+	curNode.isWord, curNode.value = false, nil
+	if curNode == t.root {
+		return true
+	}
+	if curNode.count == 0 {
+		// 叶子节点，从父节点中移除后父节点可能只剩一个子节点
+		var i, _ = parent.child(curNode.label[0])
+		copy(parent.children[i:], parent.children[i+1:])
+		parent.children[len(parent.children)-1] = nil
+		parent.children = parent.children[:len(parent.children)-1]
+		if parent != t.root && !parent.isWord && len(parent.children) == 1 {
+			parent.merge()
+		}
+	} else if len(curNode.children) == 1 {
+		curNode.merge()
+	}
+	return true
+}
+
+// Len 基数树中的单词个数
+func (t *RadixTree) Len() int {
+	return t.root.count
+}
+
+// CountPrefix 以prefix为前缀的单词个数
+func (t *RadixTree) CountPrefix(prefix string) int {
+	if n, _ := t.prefixNode(prefix); n != nil {
+		return n.(*radixNode).count
+	}
+	return 0
+}
+
+// KeysWithPrefix 按字典序返回以prefix为前缀的至多limit个单词，limit<=0时返回全部
+func (t *RadixTree) KeysWithPrefix(prefix string, limit int) []string {
+	return keysWithPrefix(t, prefix, limit)
+}
+
+// Walk 按字典序遍历以prefix为前缀的单词，fn返回false时停止遍历
+func (t *RadixTree) Walk(prefix string, fn func(word string) bool) {
+	if n, path := t.prefixNode(prefix); n != nil {
+		walkWords(n, path, "", false, fn)
+	}
+}
+
+// WalkAfter 按字典序遍历以prefix为前缀且大于after的单词，fn返回false时停止遍历
+func (t *RadixTree) WalkAfter(prefix, after string, fn func(word string) bool) {
+	if n, path := t.prefixNode(prefix); n != nil {
+		walkWords(n, path, after, true, fn)
+	}
+}
+
+// WalkValues 按字典序遍历以prefix为前缀的键及其值，fn返回false时停止遍历
+func (t *RadixTree) WalkValues(prefix string, fn func(key string, value interface{}) bool) {
+	if n, path := t.prefixNode(prefix); n != nil {
+		walk(n, []byte(path), "", false, func(key string, n walkNode) bool {
+			return fn(key, n.(*radixNode).value)
+		})
+	}
+}
+
+// nodes 节点个数，包括根节点
+func (t *RadixTree) nodes() int {
+	var cnt int
+	var dfs func(n *radixNode)
+	dfs = func(n *radixNode) {
+		cnt++
+		for _, child := range n.children {
+			dfs(child)
+		}
+	}
+	dfs(t.root)
+	return cnt
+}
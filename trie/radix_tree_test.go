@@ -0,0 +1,72 @@
+package trie
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRadixTree_SplitAndMerge(t *testing.T) {
+	var tr = NewRadixTree()
+	tr.Put("/api/users", 1)
+	tr.Put("/api/orders", 2)
+	// 根、"/api/"、"users"、"orders"
+	if n := tr.nodes(); n != 4 {
+		t.Fatalf("nodes() = %v, want 4", n)
+	}
+	tr.Put("/api", 3)
+	// "/api/"分裂为"/api"与"/"
+	if n := tr.nodes(); n != 5 {
+		t.Fatalf("nodes() = %v, want 5", n)
+	}
+	if !tr.HasPrefix("/ap") || tr.CountPrefix("/ap") != 3 || tr.CountPrefix("/api/") != 2 {
+		t.Fatalf("HasPrefix/CountPrefix mismatch")
+	}
+	if got := tr.KeysWithPrefix("/api/o", 0); !reflect.DeepEqual(got, []string{"/api/orders"}) {
+		t.Fatalf("KeysWithPrefix(\"/api/o\") = %v", got)
+	}
+	if !tr.Delete("/api/users") {
+		t.Fatalf("Delete(\"/api/users\") = false")
+	}
+	// "/"只剩一个子节点，与"orders"合并
+	if n := tr.nodes(); n != 3 {
+		t.Fatalf("nodes() = %v, want 3", n)
+	}
+	if !tr.Delete("/api") {
+		t.Fatalf("Delete(\"/api\") = false")
+	}
+	if n := tr.nodes(); n != 2 {
+		t.Fatalf("nodes() = %v, want 2", n)
+	}
+	if v, ok := tr.Get("/api/orders"); !ok || v != 2 || tr.Len() != 1 {
+		t.Fatalf("Get(\"/api/orders\") = %v, %v, Len() = %v", v, ok, tr.Len())
+	}
+}
+
+func TestRadixTree_Values(t *testing.T) {
+	var tr = NewRadixTree()
+	var kv = map[string]int{"romane": 1, "romanus": 2, "romulus": 3, "rubens": 4, "ruber": 5, "rubicon": 6}
+	for k, v := range kv {
+		tr.Put(k, v)
+	}
+	tr.Put("ruber", 50)
+	// Insert不覆盖已有的值
+	tr.Insert("rubens")
+	var (
+		keys   []string
+		values []interface{}
+	)
+	tr.WalkValues("rub", func(key string, value interface{}) bool {
+		keys = append(keys, key)
+		values = append(values, value)
+		return true
+	})
+	if !reflect.DeepEqual(keys, []string{"rubens", "ruber", "rubicon"}) || !reflect.DeepEqual(values, []interface{}{4, 50, 6}) {
+		t.Fatalf("WalkValues(\"rub\") = %v, %v", keys, values)
+	}
+	if _, ok := tr.Get("rom"); ok {
+		t.Fatalf("Get(\"rom\") found a value")
+	}
+	if tr.Len() != len(kv) {
+		t.Fatalf("Len() = %v, want %v", tr.Len(), len(kv))
+	}
+}
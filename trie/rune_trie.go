@@ -0,0 +1,180 @@
+package trie
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// RuneTrie 按Unicode字符（rune）组织的Hash前缀树
+// HashTrie等按字节组织，会把一个多字节的UTF-8字符拆到多层节点中，RuneTrie的每一层对应一个完整字符，适用于中文等文本
+// 非法的UTF-8字节按单个字节作为一层，不会与其他字符或其他非法字节混淆
+type RuneTrie struct {
+	isWord bool
+	// 以该节点为前缀的单词个数
+	count    int
+	children map[rune]*RuneTrie
+}
+
+var (
+	_ ExtendedTrie = (*RuneTrie)(nil)
+	_ walkable     = (*RuneTrie)(nil)
+)
+
+// invalidByteBase 非法UTF-8字节b对应的键为invalidByteBase+b，位于Unicode范围之外
+const invalidByteBase = utf8.MaxRune + 1
+
+// runeKey s开头的字符对应的子节点键及其字节数
+func runeKey(s string) (rune, int) {
+	var c, size = utf8.DecodeRuneInString(s)
+	if c == utf8.RuneError && size == 1 {
+		return invalidByteBase + rune(s[0]), 1
+	}
+	return c, size
+}
+
+// runeLabel 子节点键对应的原始字节
+func runeLabel(c rune) string {
+	if c >= invalidByteBase {
+		return string([]byte{byte(c - invalidByteBase)})
+	}
+	return string(c)
+}
+
+// NewRuneTrie new runeTrie
+func NewRuneTrie() *RuneTrie {
+	return &RuneTrie{children: make(map[rune]*RuneTrie)}
+}
+
+// Insert 往前缀树中添加一个元素word
+func (r *RuneTrie) Insert(word string) {
+	if r.Search(word) {
+		return
+	}
+	var curNode = r
+	curNode.count++
+	for i := 0; i < len(word); {
+		var c, size = runeKey(word[i:])
+		i += size
+		if _, has := curNode.children[c]; !has {
+			curNode.children[c] = NewRuneTrie()
+		}
+		curNode = curNode.children[c]
+		curNode.count++
+	}
+	curNode.isWord = true
+}
+
+// Search 查找前缀树中是否元素word
+func (r *RuneTrie) Search(word string) bool {
+	var _, has = r.search(word)
+	return has
+}
+
+func (r *RuneTrie) search(word string) (*RuneTrie, bool) {
+	var curNode = r.find(word)
+	if curNode == nil {
+		return nil, false
+	}
+	return curNode, curNode.isWord
+}
+
+// find 沿prefix向下查找节点，不存在时返回nil
+func (r *RuneTrie) find(prefix string) *RuneTrie {
+	var curNode = r
+	for i := 0; i < len(prefix); {
+		var c, size = runeKey(prefix[i:])
+		i += size
+		if curNode = curNode.children[c]; curNode == nil {
+			return nil
+		}
+	}
+	return curNode
+}
+
+// HasPrefix 查询前缀树中是否存在前缀prefix
+// prefix按完整字符匹配，例如插入"中国"后HasPrefix("中")为true，但"中"的UTF-8编码的前两个字节不是前缀
+func (r *RuneTrie) HasPrefix(prefix string) bool {
+	return r.find(prefix) != nil
+}
+
+// Delete 从前缀树中删除元素word，并剪除不再有单词经过的分支，word不存在时返回false
+func (r *RuneTrie) Delete(word string) bool {
+	if !r.Search(word) {
+		return false
+	}
+	var curNode = r
+	curNode.count--
+	for i := 0; i < len(word); {
+		var c, size = runeKey(word[i:])
+		i += size
+		var next = curNode.children[c]
+		next.count--
+		if next.count == 0 {
+			delete(curNode.children, c)
+			return true
+		}
+		curNode = next
+	}
+	curNode.isWord = false
+	return true
+}
+
+// Len 前缀树中的单词个数
+func (r *RuneTrie) Len() int {
+	return r.count
+}
+
+// CountPrefix 以prefix为前缀的单词个数，prefix按完整字符匹配
+func (r *RuneTrie) CountPrefix(prefix string) int {
+	if curNode := r.find(prefix); curNode != nil {
+		return curNode.count
+	}
+	return 0
+}
+
+// KeysWithPrefix 按字典序返回以prefix为前缀的至多limit个单词，limit<=0时返回全部
+func (r *RuneTrie) KeysWithPrefix(prefix string, limit int) []string {
+	return keysWithPrefix(r, prefix, limit)
+}
+
+// Walk 按字典序遍历以prefix为前缀的单词，fn返回false时停止遍历
+func (r *RuneTrie) Walk(prefix string, fn func(word string) bool) {
+	if curNode := r.find(prefix); curNode != nil {
+		walkWords(curNode, prefix, "", false, fn)
+	}
+}
+
+// WalkAfter 按字典序遍历以prefix为前缀且大于after的单词，fn返回false时停止遍历
+func (r *RuneTrie) WalkAfter(prefix, after string, fn func(word string) bool) {
+	if curNode := r.find(prefix); curNode != nil {
+		walkWords(curNode, prefix, after, true, fn)
+	}
+}
+
+func (r *RuneTrie) isKey() bool {
+	return r.isWord
+}
+
+func (r *RuneTrie) eachChild(fn func(label string, child walkNode) bool) bool {
+	// 按原始字节排序，合法字符之间的字节序与码点顺序相同，因此与其他实现的字典序一致
+	// 唯一的例外是非法字节恰好等于某个合法字符的首字节，此时以该非法字节开头的单词总是排在前面
+	var labels = make([]string, 0, len(r.children))
+	for c := range r.children {
+		labels = append(labels, runeLabel(c))
+	}
+	sort.Strings(labels)
+	for _, label := range labels {
+		var c, _ = runeKey(label)
+		if !fn(label, r.children[c]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *RuneTrie) prefixNode(prefix string) (walkNode, string) {
+	if curNode := r.find(prefix); curNode != nil {
+		return curNode, prefix
+	}
+	return nil, ""
+}
@@ -0,0 +1,167 @@
+package sensitive
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/1005281342/godatastructures/trie"
+)
+
+// DefaultMask 默认的打码字符
+const DefaultMask = '*'
+
+// Hit 一次命中
+type Hit struct {
+	// Word 命中的敏感词，为词表中的原始形式
+	Word string
+	// Start, End 原文中的字节区间[Start, End)，包含夹在敏感词字符之间的干扰字符
+	Start int
+	End   int
+}
+
+// Filter 敏感词过滤器
+// 词表与待检测文本先做同样的归一化：全角字符转为半角、转为小写、去掉干扰字符，
+// 再用AC自动机一次扫描找出所有命中，最后把命中位置映射回原文
+// Find、Contains、Mask只读，可以并发调用；AddWords、WithNoise、WithMask不能与其他方法并发调用
+type Filter struct {
+	words []string
+	noise map[rune]bool
+	mask  rune
+	// 由归一化后的词表构建，词表或干扰字符变化时立即重新构建
+	ac *trie.AhoCorasick
+}
+
+// NewFilter 由敏感词表words创建过滤器
+func NewFilter(words []string) *Filter {
+	var f = &Filter{noise: make(map[rune]bool), mask: DefaultMask}
+	f.AddWords(words...)
+	return f
+}
+
+// AddWords 添加敏感词
+func (f *Filter) AddWords(words ...string) {
+	f.words = append(f.words, words...)
+	f.build()
+}
+
+// WithNoise 指定干扰字符，敏感词的字符之间夹杂这些字符时仍然命中，例如"b*a*d"
+func (f *Filter) WithNoise(chars string) {
+	for _, r := range chars {
+		f.noise[fold(r)] = true
+	}
+	f.build()
+}
+
+// WithMask 指定打码字符
+func (f *Filter) WithMask(mask rune) {
+	f.mask = mask
+}
+
+// fold 全角转半角并转为小写
+func fold(r rune) rune {
+	switch {
+	case r == '　':
+		// 全角空格
+		r = ' '
+	case r >= '！' && r <= '～':
+		r -= 0xFEE0
+	}
+	return unicode.ToLower(r)
+}
+
+// normalized 归一化后的文本，以及其中每个字节所在字符在原文中的字节区间
+type normalized struct {
+	text  []byte
+	start []int
+	end   []int
+}
+
+// normalize 归一化文本，去掉干扰字符
+func (f *Filter) normalize(text string) normalized {
+	var (
+		n   = normalized{text: make([]byte, 0, len(text))}
+		buf [utf8.UTFMax]byte
+	)
+	for i, r := range text {
+		var size = utf8.RuneLen(r)
+		if r == utf8.RuneError {
+			// 非法字节按单个字节计
+			_, size = utf8.DecodeRuneInString(text[i:])
+		}
+		if r = fold(r); f.noise[r] {
+			continue
+		}
+		var l = utf8.EncodeRune(buf[:], r)
+		n.text = append(n.text, buf[:l]...)
+		for j := 0; j < l; j++ {
+			n.start = append(n.start, i)
+			n.end = append(n.end, i+size)
+		}
+	}
+	return n
+}
+
+// build 由归一化后的词表构建AC自动机
+func (f *Filter) build() {
+	var patterns = make([]string, len(f.words))
+	for i, w := range f.words {
+		// 归一化后为空的词会被忽略
+		patterns[i] = string(f.normalize(w).text)
+	}
+	f.ac = trie.NewAhoCorasick(patterns)
+}
+
+// Find 返回text中的所有命中（包括相互重叠的），按起点升序，起点相同时短的在前
+func (f *Filter) Find(text string) []Hit {
+	var (
+		n    = f.normalize(text)
+		hits []Hit
+	)
+	for _, m := range f.ac.FindAll(string(n.text)) {
+		hits = append(hits, Hit{Word: f.words[m.Pattern], Start: n.start[m.Start], End: n.end[m.End-1]})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Start != hits[j].Start {
+			return hits[i].Start < hits[j].Start
+		}
+		return hits[i].End < hits[j].End
+	})
+	return hits
+}
+
+// Contains text中是否含有敏感词
+func (f *Filter) Contains(text string) bool {
+	return f.ac.Contains(string(f.normalize(text).text))
+}
+
+// Mask 返回将所有命中区间内的每个字符替换为打码字符后的文本，以及所有命中
+func (f *Filter) Mask(text string) (string, []Hit) {
+	var hits = f.Find(text)
+	if len(hits) == 0 {
+		return text, nil
+	}
+	var (
+		sb strings.Builder
+		// 已处理到的原文位置，以及当前打码区间的结束位置
+		pos, end int
+	)
+	sb.Grow(len(text))
+	for _, h := range hits {
+		if h.Start > end {
+			sb.WriteString(text[pos:h.Start])
+			pos = h.Start
+		}
+		if h.End > end {
+			end = h.End
+		}
+		for pos < end {
+			var _, size = utf8.DecodeRuneInString(text[pos:])
+			sb.WriteRune(f.mask)
+			pos += size
+		}
+	}
+	sb.WriteString(text[pos:])
+	return sb.String(), hits
+}
@@ -0,0 +1,81 @@
+package sensitive
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestFilter_Find(t *testing.T) {
+	var f = NewFilter([]string{"bad", "BADGE", "坏人", "ab"})
+	f.WithNoise("*-· ")
+	tests := []struct {
+		name string
+		text string
+		want []Hit
+	}{
+		{name: "none", text: "good", want: nil},
+		{name: "plain", text: "a bad day", want: []Hit{{Word: "ab", Start: 0, End: 3}, {Word: "bad", Start: 2, End: 5}}},
+		{name: "noise", text: "b*a*d!", want: []Hit{{Word: "bad", Start: 0, End: 5}}},
+		{name: "overlapping", text: "xbadge", want: []Hit{{Word: "bad", Start: 1, End: 4}, {Word: "BADGE", Start: 1, End: 6}}},
+		{name: "case and width", text: "ＢａＤ", want: []Hit{{Word: "bad", Start: 0, End: 9}}},
+		{name: "chinese", text: "他是坏·人", want: []Hit{{Word: "坏人", Start: 6, End: 14}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := f.Find(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Find(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+	if !f.Contains("B-A-D") || f.Contains("b+a+d") {
+		t.Errorf("Contains() mismatch")
+	}
+}
+
+func TestFilter_Mask(t *testing.T) {
+	var f = NewFilter([]string{"bad", "dog", "坏人"})
+	f.WithNoise("*")
+	tests := []struct {
+		text string
+		want string
+		hits int
+	}{
+		{text: "good", want: "good", hits: 0},
+		{text: "b*a*d boy", want: "***** boy", hits: 1},
+		// 相邻与重叠的命中合并打码
+		{text: "baddog!", want: "******!", hits: 2},
+		{text: "BADOG", want: "*****", hits: 2},
+		{text: "你是坏*人吗", want: "你是***吗", hits: 1},
+	}
+	for _, tt := range tests {
+		var got, hits = f.Mask(tt.text)
+		if got != tt.want || len(hits) != tt.hits {
+			t.Errorf("Mask(%q) = %q, %d hits, want %q, %d hits", tt.text, got, len(hits), tt.want, tt.hits)
+		}
+	}
+	f.AddWords("boy")
+	f.WithMask('#')
+	if got, _ := f.Mask("bad boy"); got != "### ###" {
+		t.Errorf("Mask(\"bad boy\") after AddWords = %q", got)
+	}
+}
+
+func TestFilter_Concurrent(t *testing.T) {
+	var f = NewFilter([]string{"bad", "坏人"})
+	f.WithNoise("*")
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if got, _ := f.Mask("b*ad 坏人"); got != "**** **" {
+					t.Errorf("Mask() = %q, want %q", got, "**** **")
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
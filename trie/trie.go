@@ -10,10 +10,16 @@ const (
 	EmListTrie
 	// EmArrayTrie 数组前缀树
 	EmArrayTrie
+	// EmRuneTrie 按Unicode字符组织的前缀树
+	EmRuneTrie
+	// EmRadixTrie 基数树（压缩前缀树）
+	EmRadixTrie
+	// EmTSTTrie 三分搜索树
+	EmTSTTrie
 )
 
 // NewTrie 创建一个Trie
-func NewTrie(t Type) Trie {
+func NewTrie(t Type) ExtendedTrie {
 	switch t {
 	case EmHashTrie:
 		return NewHashTrie()
@@ -21,6 +27,12 @@ func NewTrie(t Type) Trie {
 		return NewListTrie()
 	case EmArrayTrie:
 		return NewArrayTrie()
+	case EmRuneTrie:
+		return NewRuneTrie()
+	case EmRadixTrie:
+		return NewRadixTree()
+	case EmTSTTrie:
+		return NewTSTTrie()
 	default:
 		// HashTrie较为通用
 		return NewHashTrie()
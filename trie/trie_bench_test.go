@@ -0,0 +1,69 @@
+package trie
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// benchWords 由小写字母组成的随机单词，ArrayTrie的默认字母表也能使用
+func benchWords(n int) []string {
+	var (
+		r     = rand.New(rand.NewSource(1))
+		words = make([]string, n)
+	)
+	for i := range words {
+		var b = make([]byte, 4+r.Intn(12))
+		for j := range b {
+			b[j] = byte('a' + r.Intn(26))
+		}
+		words[i] = string(b)
+	}
+	return words
+}
+
+func BenchmarkTrie_Insert(b *testing.B) {
+	var words = benchWords(10000)
+	for _, tt := range allTypes {
+		b.Run(tt.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var tr = NewTrie(tt.t)
+				for _, w := range words {
+					tr.Insert(w)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkTrie_Search(b *testing.B) {
+	var words = benchWords(10000)
+	for _, tt := range allTypes {
+		b.Run(tt.name, func(b *testing.B) {
+			var tr = NewTrie(tt.t)
+			for _, w := range words {
+				tr.Insert(w)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				tr.Search(words[i%len(words)])
+			}
+		})
+	}
+}
+
+func BenchmarkTrie_KeysWithPrefix(b *testing.B) {
+	var words = benchWords(10000)
+	for _, tt := range allTypes {
+		b.Run(tt.name, func(b *testing.B) {
+			var tr = NewTrie(tt.t)
+			for _, w := range words {
+				tr.Insert(w)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				tr.KeysWithPrefix(words[i%len(words)][:2], 10)
+			}
+		})
+	}
+}
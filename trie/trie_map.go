@@ -0,0 +1,170 @@
+package trie
+
+// Aggregator 前缀聚合方式，TrieMap在每个节点上维护其子树内所有值的聚合结果
+// Merge必须满足交换律与结合律，且Zero为Merge的单位元
+type Aggregator struct {
+	// Zero 空子树的聚合值
+	Zero interface{}
+	// Lift 将一个键的值转换为聚合值
+	Lift func(value interface{}) interface{}
+	// Merge 合并两个聚合值
+	Merge func(a, b interface{}) interface{}
+}
+
+var (
+	// CountAggregator 统计键的个数，聚合值为int
+	CountAggregator = &Aggregator{
+		Zero:  0,
+		Lift:  func(interface{}) interface{} { return 1 },
+		Merge: func(a, b interface{}) interface{} { return a.(int) + b.(int) },
+	}
+	// SumAggregator 对int类型的值求和，聚合值为int
+	SumAggregator = &Aggregator{
+		Zero:  0,
+		Lift:  func(value interface{}) interface{} { return value },
+		Merge: func(a, b interface{}) interface{} { return a.(int) + b.(int) },
+	}
+	// MaxAggregator 求int类型的值的最大值，聚合值为int，空子树为nil
+	MaxAggregator = &Aggregator{
+		Zero: nil,
+		Lift: func(value interface{}) interface{} { return value },
+		Merge: func(a, b interface{}) interface{} {
+			if a == nil || (b != nil && b.(int) > a.(int)) {
+				return b
+			}
+			return a
+		},
+	}
+)
+
+// TrieMap 键为字符串、值为任意类型的前缀树
+// 每个节点缓存子树的聚合值，按前缀聚合的查询只需找到前缀对应的节点，时间复杂度为O(len(prefix))
+type TrieMap struct {
+	root *trieMapNode
+	agg  *Aggregator
+	size int
+}
+
+type trieMapNode struct {
+	hasValue bool
+	value    interface{}
+	// 子树内所有值的聚合结果
+	agg      interface{}
+	children map[byte]*trieMapNode
+}
+
+// NewTrieMap 创建使用聚合方式agg的TrieMap，agg为nil时使用CountAggregator
+func NewTrieMap(agg *Aggregator) *TrieMap {
+	if agg == nil {
+		agg = CountAggregator
+	}
+	return &TrieMap{root: newTrieMapNode(agg), agg: agg}
+}
+
+func newTrieMapNode(agg *Aggregator) *trieMapNode {
+	return &trieMapNode{agg: agg.Zero, children: make(map[byte]*trieMapNode)}
+}
+
+// Len 键的个数
+func (m *TrieMap) Len() int {
+	return m.size
+}
+
+// find 沿prefix向下查找节点，不存在时返回nil
+func (m *TrieMap) find(prefix string) *trieMapNode {
+	var curNode = m.root
+	for i := 0; i < len(prefix); i++ {
+		if curNode = curNode.children[prefix[i]]; curNode == nil {
+			return nil
+		}
+	}
+	return curNode
+}
+
+// Get 键key对应的值
+func (m *TrieMap) Get(key string) (interface{}, bool) {
+	if curNode := m.find(key); curNode != nil && curNode.hasValue {
+		return curNode.value, true
+	}
+	return nil, false
+}
+
+// Put 设置键key的值为value，已存在时覆盖
+func (m *TrieMap) Put(key string, value interface{}) {
+	var (
+		curNode = m.root
+		path    = make([]*trieMapNode, 0, len(key)+1)
+	)
+	path = append(path, curNode)
+	for i := 0; i < len(key); i++ {
+		var next = curNode.children[key[i]]
+		if next == nil {
+			next = newTrieMapNode(m.agg)
+			curNode.children[key[i]] = next
+		}
+		curNode = next
+		path = append(path, curNode)
+	}
+	if !curNode.hasValue {
+		m.size++
+	}
+	curNode.hasValue, curNode.value = true, value
+	m.refresh(path)
+}
+
+// Delete 删除键key，并剪除不再有键经过的分支，key不存在时返回false
+func (m *TrieMap) Delete(key string) bool {
+	var (
+		curNode = m.root
+		path    = make([]*trieMapNode, 0, len(key)+1)
+	)
+	path = append(path, curNode)
+	for i := 0; i < len(key); i++ {
+		if curNode = curNode.children[key[i]]; curNode == nil {
+			return false
+		}
+		path = append(path, curNode)
+	}
+	if !curNode.hasValue {
+		return false
+	}
+	curNode.hasValue, curNode.value = false, nil
+	m.size--
+	// 自底向上剪除既无值也无子节点的节点
+	for i := len(key); i > 0 && !path[i].hasValue && len(path[i].children) == 0; i-- {
+		delete(path[i-1].children, key[i-1])
+		path = path[:i]
+	}
+	m.refresh(path)
+	return true
+}
+
+// refresh 自底向上重新计算路径上各节点的聚合值
+func (m *TrieMap) refresh(path []*trieMapNode) {
+	for i := len(path) - 1; i >= 0; i-- {
+		var (
+			curNode = path[i]
+			agg     = m.agg.Zero
+		)
+		if curNode.hasValue {
+			agg = m.agg.Merge(agg, m.agg.Lift(curNode.value))
+		}
+		for _, child := range curNode.children {
+			agg = m.agg.Merge(agg, child.agg)
+		}
+		curNode.agg = agg
+	}
+}
+
+// HasPrefix 是否存在以prefix为前缀的键
+func (m *TrieMap) HasPrefix(prefix string) bool {
+	return m.find(prefix) != nil
+}
+
+// Aggregate 以prefix为前缀的所有键的值的聚合结果，没有这样的键时返回聚合方式的Zero
+func (m *TrieMap) Aggregate(prefix string) interface{} {
+	if curNode := m.find(prefix); curNode != nil {
+		return curNode.agg
+	}
+	return m.agg.Zero
+}
@@ -0,0 +1,73 @@
+package trie
+
+import (
+	"testing"
+)
+
+func TestTrieMap(t *testing.T) {
+	// LeetCode677 键值映射
+	var m = NewTrieMap(SumAggregator)
+	m.Put("apple", 3)
+	if got := m.Aggregate("ap"); got != 3 {
+		t.Fatalf("Aggregate(\"ap\") = %v, want 3", got)
+	}
+	m.Put("app", 2)
+	m.Put("apple", 5)
+	if got := m.Aggregate("ap"); got != 7 {
+		t.Fatalf("Aggregate(\"ap\") = %v, want 7", got)
+	}
+	if v, ok := m.Get("app"); !ok || v != 2 {
+		t.Fatalf("Get(\"app\") = %v, %v", v, ok)
+	}
+	if _, ok := m.Get("appl"); ok {
+		t.Fatalf("Get(\"appl\") found a value")
+	}
+	if m.Len() != 2 {
+		t.Fatalf("Len() = %v, want 2", m.Len())
+	}
+	if !m.Delete("apple") || m.Delete("apple") || m.HasPrefix("appl") {
+		t.Fatalf("Delete(\"apple\") mismatch")
+	}
+	if got := m.Aggregate("a"); got != 2 {
+		t.Fatalf("Aggregate(\"a\") = %v, want 2", got)
+	}
+	if got := m.Aggregate("b"); got != 0 {
+		t.Fatalf("Aggregate(\"b\") = %v, want 0", got)
+	}
+}
+
+func TestTrieMap_Aggregators(t *testing.T) {
+	var kv = map[string]int{"a": 4, "ab": 9, "abc": 1, "abd": 7, "b": 3}
+	tests := []struct {
+		name   string
+		agg    *Aggregator
+		prefix string
+		want   interface{}
+	}{
+		{name: "count", agg: CountAggregator, prefix: "ab", want: 3},
+		{name: "count empty", agg: CountAggregator, prefix: "c", want: 0},
+		{name: "sum", agg: SumAggregator, prefix: "", want: 24},
+		{name: "max", agg: MaxAggregator, prefix: "ab", want: 9},
+		{name: "max empty", agg: MaxAggregator, prefix: "abx", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m = NewTrieMap(tt.agg)
+			for k, v := range kv {
+				m.Put(k, v)
+			}
+			if got := m.Aggregate(tt.prefix); got != tt.want {
+				t.Errorf("Aggregate(%q) = %v, want %v", tt.prefix, got, tt.want)
+			}
+		})
+	}
+	// 删除最大值后需要重新计算
+	var m = NewTrieMap(MaxAggregator)
+	for k, v := range kv {
+		m.Put(k, v)
+	}
+	m.Delete("ab")
+	if got := m.Aggregate("a"); got != 7 {
+		t.Errorf("Aggregate(\"a\") after Delete = %v, want 7", got)
+	}
+}
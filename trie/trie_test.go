@@ -1 +1,245 @@
 package trie
+
+import (
+	"reflect"
+	"testing"
+)
+
+// allTypes NewTrie支持的所有前缀树类型
+var allTypes = []struct {
+	name string
+	t    Type
+}{
+	{name: "hash", t: EmHashTrie},
+	{name: "list", t: EmListTrie},
+	{name: "array", t: EmArrayTrie},
+	{name: "rune", t: EmRuneTrie},
+	{name: "radix", t: EmRadixTrie},
+	{name: "tst", t: EmTSTTrie},
+}
+
+func TestTrie(t *testing.T) {
+	var words = []string{"app", "apple", "banana", "band", "b"}
+	for _, tt := range allTypes {
+		t.Run(tt.name, func(t *testing.T) {
+			var tr = NewTrie(tt.t)
+			for _, w := range words {
+				tr.Insert(w)
+			}
+			for _, w := range words {
+				if !tr.Search(w) {
+					t.Errorf("Search(%q) = false, want true", w)
+				}
+			}
+			for _, w := range []string{"ap", "appl", "bandana", "c", ""} {
+				if tr.Search(w) {
+					t.Errorf("Search(%q) = true, want false", w)
+				}
+			}
+			for _, p := range []string{"", "a", "appl", "ban", "band"} {
+				if !tr.HasPrefix(p) {
+					t.Errorf("HasPrefix(%q) = false, want true", p)
+				}
+			}
+			for _, p := range []string{"c", "bands", "applf"} {
+				if tr.HasPrefix(p) {
+					t.Errorf("HasPrefix(%q) = true, want false", p)
+				}
+			}
+		})
+	}
+}
+
+func TestRuneTrie(t *testing.T) {
+	var tr = NewRuneTrie()
+	tr.Insert("中国")
+	tr.Insert("中华人民")
+	tests := []struct {
+		prefix string
+		want   bool
+	}{
+		{prefix: "中", want: true},
+		{prefix: "中华", want: true},
+		{prefix: "中华人民", want: true},
+		{prefix: "华", want: false},
+		// "中"的UTF-8编码的前两个字节
+		{prefix: "中"[:2], want: false},
+	}
+	for _, tt := range tests {
+		if got := tr.HasPrefix(tt.prefix); got != tt.want {
+			t.Errorf("HasPrefix(%q) = %v, want %v", tt.prefix, got, tt.want)
+		}
+	}
+	if !tr.Search("中国") || tr.Search("中") {
+		t.Errorf("Search() mismatch")
+	}
+}
+
+func TestRuneTrie_InvalidUTF8(t *testing.T) {
+	var (
+		tr    = NewRuneTrie()
+		words = []string{"\xfe", "\xff", "a\xffb", "a\xfeb", "\ufffd", "中"}
+	)
+	for _, w := range words {
+		tr.Insert(w)
+	}
+	if tr.Len() != len(words) {
+		t.Fatalf("Len() = %v, want %v", tr.Len(), len(words))
+	}
+	for _, w := range words {
+		if !tr.Search(w) {
+			t.Errorf("Search(%q) = false", w)
+		}
+	}
+	var want = []string{"a\xfeb", "a\xffb", "中", "\ufffd", "\xfe", "\xff"}
+	if got := tr.KeysWithPrefix("", 0); !reflect.DeepEqual(got, want) {
+		t.Errorf("KeysWithPrefix() = %q, want %q", got, want)
+	}
+	if got := tr.CountPrefix("a\xff"); got != 1 {
+		t.Errorf("CountPrefix(%q) = %v, want 1", "a\xff", got)
+	}
+	if !tr.Delete("\xff") || tr.Search("\xff") || !tr.Search("\xfe") || tr.Len() != len(words)-1 {
+		t.Errorf("Delete(%q) mismatch", "\xff")
+	}
+}
+
+func TestArrayTrie_Alphabet(t *testing.T) {
+	if _, err := NewArrayTrieWithAlphabet(""); err != ErrInvalidAlphabet {
+		t.Fatalf("NewArrayTrieWithAlphabet(\"\") error = %v, want %v", err, ErrInvalidAlphabet)
+	}
+	if _, err := NewArrayTrieWithAlphabet("abca"); err != ErrInvalidAlphabet {
+		t.Fatalf("NewArrayTrieWithAlphabet(\"abca\") error = %v, want %v", err, ErrInvalidAlphabet)
+	}
+	var tr, err = NewArrayTrieWithAlphabet("0123456789.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = tr.TryInsert("3.14"); err != nil {
+		t.Fatal(err)
+	}
+	if err = tr.TryInsert("3e8"); err != ErrOutOfAlphabet {
+		t.Fatalf("TryInsert(\"3e8\") error = %v, want %v", err, ErrOutOfAlphabet)
+	}
+	// 字母表以外的字符不会panic
+	var lower = NewArrayTrie()
+	lower.Insert("Hello")
+	if lower.Search("Hello") || lower.HasPrefix("H") || !tr.Search("3.14") || tr.HasPrefix("3e") {
+		t.Errorf("out-of-alphabet words mismatch")
+	}
+}
+
+func TestTrie_Delete(t *testing.T) {
+	var words = []string{"app", "apple", "apply", "banana", "band", "b", ""}
+	for _, tt := range allTypes {
+		t.Run(tt.name, func(t *testing.T) {
+			var tr = NewTrie(tt.t)
+			for _, w := range words {
+				tr.Insert(w)
+			}
+			// 重复插入不计数
+			tr.Insert("app")
+			if tr.Len() != len(words) {
+				t.Fatalf("Len() = %v, want %v", tr.Len(), len(words))
+			}
+			counts := []struct {
+				prefix string
+				want   int
+			}{
+				{prefix: "", want: 7},
+				{prefix: "app", want: 3},
+				{prefix: "appl", want: 2},
+				{prefix: "b", want: 3},
+				{prefix: "ban", want: 2},
+				{prefix: "c", want: 0},
+			}
+			for _, c := range counts {
+				if got := tr.CountPrefix(c.prefix); got != c.want {
+					t.Errorf("CountPrefix(%q) = %v, want %v", c.prefix, got, c.want)
+				}
+			}
+			if tr.Delete("ap") || tr.Delete("bandana") {
+				t.Fatalf("Delete() of missing word = true")
+			}
+			// 删除作为前缀的单词只取消标记
+			if !tr.Delete("app") || tr.Search("app") || !tr.Search("apple") || tr.CountPrefix("app") != 2 {
+				t.Fatalf("Delete(\"app\") mismatch")
+			}
+			// 删除叶子单词剪除分支
+			if !tr.Delete("apply") || tr.HasPrefix("apply") || !tr.HasPrefix("appl") {
+				t.Fatalf("Delete(\"apply\") mismatch")
+			}
+			if !tr.Delete("apple") || tr.HasPrefix("a") {
+				t.Fatalf("Delete(\"apple\") did not prune the branch")
+			}
+			if !tr.Delete("") || tr.Search("") || tr.Len() != 3 {
+				t.Fatalf("Delete(\"\") mismatch, Len() = %v", tr.Len())
+			}
+			if !tr.Delete("band") || tr.Search("band") || !tr.Search("banana") || tr.CountPrefix("ban") != 1 {
+				t.Fatalf("Delete(\"band\") mismatch")
+			}
+		})
+	}
+}
+
+func TestTrie_KeysWithPrefix(t *testing.T) {
+	var words = []string{"band", "apple", "b", "app", "banana", "bandana", "apply", "cat"}
+	for _, tt := range allTypes {
+		t.Run(tt.name, func(t *testing.T) {
+			var tr = NewTrie(tt.t)
+			for _, w := range words {
+				tr.Insert(w)
+			}
+			tests := []struct {
+				prefix string
+				limit  int
+				want   []string
+			}{
+				{prefix: "", limit: 0, want: []string{"app", "apple", "apply", "b", "banana", "band", "bandana", "cat"}},
+				{prefix: "app", limit: 2, want: []string{"app", "apple"}},
+				{prefix: "ban", limit: 0, want: []string{"banana", "band", "bandana"}},
+				{prefix: "band", limit: 1, want: []string{"band"}},
+				{prefix: "d", limit: 0, want: nil},
+			}
+			for _, c := range tests {
+				if got := tr.KeysWithPrefix(c.prefix, c.limit); !reflect.DeepEqual(got, c.want) {
+					t.Errorf("KeysWithPrefix(%q, %d) = %v, want %v", c.prefix, c.limit, got, c.want)
+				}
+			}
+			var got []string
+			tr.WalkAfter("", "apply", func(word string) bool {
+				got = append(got, word)
+				return len(got) < 3
+			})
+			if want := []string{"b", "banana", "band"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("WalkAfter(\"\", \"apply\") = %v, want %v", got, want)
+			}
+			got = nil
+			tr.WalkAfter("b", "bal", func(word string) bool {
+				got = append(got, word)
+				return true
+			})
+			if want := []string{"banana", "band", "bandana"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("WalkAfter(\"b\", \"bal\") = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestCursor(t *testing.T) {
+	var tr = NewHashTrie()
+	for _, w := range []string{"a", "ab", "abc", "abd", "b", "ba"} {
+		tr.Insert(w)
+	}
+	var c = NewCursor(tr, "a")
+	if got := c.Next(2); !reflect.DeepEqual(got, []string{"a", "ab"}) || c.Done() {
+		t.Fatalf("Next(2) = %v, Done() = %v", got, c.Done())
+	}
+	// 从保存的位置恢复
+	c = NewCursorAfter(tr, "a", c.Last())
+	if got := c.Next(2); !reflect.DeepEqual(got, []string{"abc", "abd"}) || c.Done() {
+		t.Fatalf("Next(2) = %v, Done() = %v", got, c.Done())
+	}
+	if got := c.Next(2); got != nil || !c.Done() {
+		t.Fatalf("Next(2) = %v, Done() = %v", got, c.Done())
+	}
+}
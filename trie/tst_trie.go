@@ -0,0 +1,253 @@
+package trie
+
+// TSTTrie 三分搜索树（ternary search tree）
+// 每个节点存放一个字符，lo与hi子树中是同一位置上更小与更大的字符，eq子树中是下一个位置的字符，
+// 每个节点只有三个指针，大字母表下比数组、哈希子节点节省空间，同时天然有序
+type TSTTrie struct {
+	root *tstNode
+	// 空串是否为单词
+	emptyWord bool
+	size      int
+}
+
+type tstNode struct {
+	char       byte
+	lo, eq, hi *tstNode
+	isWord     bool
+	// 以从根到该节点（含该字符）的字符串为前缀的单词个数
+	count int
+}
+
+var (
+	_ ExtendedTrie = (*TSTTrie)(nil)
+	_ walkable     = (*TSTTrie)(nil)
+)
+
+// NewTSTTrie new tstTrie
+func NewTSTTrie() *TSTTrie {
+	return new(TSTTrie)
+}
+
+// Insert 往三分搜索树中添加一个元素word
+func (t *TSTTrie) Insert(word string) {
+	if t.Search(word) {
+		return
+	}
+	t.size++
+	if len(word) == 0 {
+		t.emptyWord = true
+		return
+	}
+	t.root = t.insert(t.root, word, 0)
+}
+
+func (t *TSTTrie) insert(n *tstNode, word string, i int) *tstNode {
+	var c = word[i]
+	if n == nil {
+		n = &tstNode{char: c}
+	}
+	switch {
+	case c < n.char:
+		n.lo = t.insert(n.lo, word, i)
+	case c > n.char:
+		n.hi = t.insert(n.hi, word, i)
+	default:
+		n.count++
+		if i+1 < len(word) {
+			n.eq = t.insert(n.eq, word, i+1)
+		} else {
+			n.isWord = true
+		}
+	}
+	return n
+}
+
+// find 前缀prefix的最后一个字符对应的节点，prefix不能为空，不存在时返回nil
+func (t *TSTTrie) find(prefix string) *tstNode {
+	var (
+		curNode = t.root
+		i       int
+	)
+	for curNode != nil {
+		switch c := prefix[i]; {
+		case c < curNode.char:
+			curNode = curNode.lo
+		case c > curNode.char:
+			curNode = curNode.hi
+		default:
+			if i++; i == len(prefix) {
+				return curNode
+			}
+			curNode = curNode.eq
+		}
+	}
+	return nil
+}
+
+// Search 查找三分搜索树中是否元素word
+func (t *TSTTrie) Search(word string) bool {
+	if len(word) == 0 {
+		return t.emptyWord
+	}
+	var curNode = t.find(word)
+	return curNode != nil && curNode.isWord
+}
+
+// HasPrefix 查询三分搜索树中是否存在前缀prefix
+func (t *TSTTrie) HasPrefix(prefix string) bool {
+	return len(prefix) == 0 || t.find(prefix) != nil
+}
+
+// Delete 删除元素word，并从所在的二叉搜索树中摘除不再有单词经过的节点，word不存在时返回false
+func (t *TSTTrie) Delete(word string) bool {
+	if !t.Search(word) {
+		return false
+	}
+	t.size--
+	if len(word) == 0 {
+		t.emptyWord = false
+		return true
+	}
+	t.root = t.delete(t.root, word, 0)
+	return true
+}
+
+func (t *TSTTrie) delete(n *tstNode, word string, i int) *tstNode {
+	switch c := word[i]; {
+	case c < n.char:
+		n.lo = t.delete(n.lo, word, i)
+	case c > n.char:
+		n.hi = t.delete(n.hi, word, i)
+	default:
+		if n.count--; n.count == 0 {
+			return joinTST(n.lo, n.hi)
+		}
+		if i+1 < len(word) {
+			n.eq = t.delete(n.eq, word, i+1)
+		} else {
+			n.isWord = false
+		}
+	}
+	return n
+}
+
+// joinTST 合并同一位置上的两棵二叉搜索树，hi中的字符都大于lo中的字符
+func joinTST(lo, hi *tstNode) *tstNode {
+	if lo == nil {
+		return hi
+	}
+	var r = lo
+	for r.hi != nil {
+		r = r.hi
+	}
+	r.hi = hi
+	return lo
+}
+
+// Len 三分搜索树中的单词个数
+func (t *TSTTrie) Len() int {
+	return t.size
+}
+
+// CountPrefix 以prefix为前缀的单词个数
+func (t *TSTTrie) CountPrefix(prefix string) int {
+	if len(prefix) == 0 {
+		return t.size
+	}
+	if curNode := t.find(prefix); curNode != nil {
+		return curNode.count
+	}
+	return 0
+}
+
+// KeysWithPrefix 按字典序返回以prefix为前缀的至多limit个单词，limit<=0时返回全部
+func (t *TSTTrie) KeysWithPrefix(prefix string, limit int) []string {
+	return keysWithPrefix(t, prefix, limit)
+}
+
+// Walk 按字典序遍历以prefix为前缀的单词，fn返回false时停止遍历
+func (t *TSTTrie) Walk(prefix string, fn func(word string) bool) {
+	if n, path := t.prefixNode(prefix); n != nil {
+		walkWords(n, path, "", false, fn)
+	}
+}
+
+// WalkAfter 按字典序遍历以prefix为前缀且大于after的单词，fn返回false时停止遍历
+func (t *TSTTrie) WalkAfter(prefix, after string, fn func(word string) bool) {
+	if n, path := t.prefixNode(prefix); n != nil {
+		walkWords(n, path, after, true, fn)
+	}
+}
+
+// prefixNode 空前缀对应一个以整棵树为eq子树的虚拟根节点
+func (t *TSTTrie) prefixNode(prefix string) (walkNode, string) {
+	if len(prefix) == 0 {
+		return &tstNode{eq: t.root, isWord: t.emptyWord, count: t.size}, ""
+	}
+	if curNode := t.find(prefix); curNode != nil {
+		return curNode, prefix
+	}
+	return nil, ""
+}
+
+// 作为前缀树节点时，tstNode表示读入char之后的状态，其子节点为eq这棵二叉搜索树中的所有节点
+func (n *tstNode) isKey() bool {
+	return n.isWord
+}
+
+func (n *tstNode) eachChild(fn func(label string, child walkNode) bool) bool {
+	return n.eq.inorder(fn)
+}
+
+// inorder 按字符升序遍历以n为根的二叉搜索树
+func (n *tstNode) inorder(fn func(label string, child walkNode) bool) bool {
+	if n == nil {
+		return true
+	}
+	return n.lo.inorder(fn) && fn(byteLabels[n.char], n) && n.hi.inorder(fn)
+}
+
+// NearNeighbors 按字典序返回与word长度相同、且汉明距离（对应位置不同的字节数）不超过d的所有单词
+func (t *TSTTrie) NearNeighbors(word string, d int) []string {
+	if d < 0 {
+		return nil
+	}
+	if len(word) == 0 {
+		if t.emptyWord {
+			return []string{""}
+		}
+		return nil
+	}
+	var ans []string
+	t.nearSearch(t.root, word, 0, d, make([]byte, 0, len(word)), &ans)
+	return ans
+}
+
+func (t *TSTTrie) nearSearch(n *tstNode, word string, i, d int, path []byte, ans *[]string) {
+	if n == nil || d < 0 {
+		return
+	}
+	var c = word[i]
+	// 还有剩余的距离时，同一位置上的其他字符也可能匹配
+	if d > 0 || c < n.char {
+		t.nearSearch(n.lo, word, i, d, path, ans)
+	}
+	var rest = d
+	if c != n.char {
+		rest--
+	}
+	if rest >= 0 {
+		path = append(path, n.char)
+		if i+1 == len(word) {
+			if n.isWord {
+				*ans = append(*ans, string(path))
+			}
+		} else {
+			t.nearSearch(n.eq, word, i+1, rest, path, ans)
+		}
+		path = path[:len(path)-1]
+	}
+	if d > 0 || c > n.char {
+		t.nearSearch(n.hi, word, i, d, path, ans)
+	}
+}
@@ -0,0 +1,52 @@
+package trie
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTSTTrie_NearNeighbors(t *testing.T) {
+	var tr = NewTSTTrie()
+	for _, w := range []string{"cat", "cut", "cot", "cart", "bat", "car", "dog", "ca"} {
+		tr.Insert(w)
+	}
+	tests := []struct {
+		word string
+		d    int
+		want []string
+	}{
+		{word: "cat", d: 0, want: []string{"cat"}},
+		{word: "cat", d: 1, want: []string{"bat", "car", "cat", "cot", "cut"}},
+		{word: "cbt", d: 1, want: []string{"cat", "cot", "cut"}},
+		{word: "dot", d: 1, want: []string{"cot", "dog"}},
+		{word: "xyz", d: 2, want: nil},
+		{word: "xyz", d: 3, want: []string{"bat", "car", "cat", "cot", "cut", "dog"}},
+	}
+	for _, tt := range tests {
+		if got := tr.NearNeighbors(tt.word, tt.d); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("NearNeighbors(%q, %d) = %v, want %v", tt.word, tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestTSTTrie_DeleteJoin(t *testing.T) {
+	var tr = NewTSTTrie()
+	// 根节点为'm'，左右子树都不为空
+	for _, w := range []string{"m", "c", "x", "a", "e", "z"} {
+		tr.Insert(w)
+	}
+	if !tr.Delete("m") || tr.Search("m") {
+		t.Fatalf("Delete(\"m\") mismatch")
+	}
+	if got := tr.KeysWithPrefix("", 0); !reflect.DeepEqual(got, []string{"a", "c", "e", "x", "z"}) {
+		t.Fatalf("KeysWithPrefix(\"\", 0) = %v", got)
+	}
+	for _, w := range []string{"a", "c", "e", "x", "z"} {
+		if !tr.Delete(w) {
+			t.Fatalf("Delete(%q) = false", w)
+		}
+	}
+	if tr.root != nil || tr.Len() != 0 {
+		t.Fatalf("tree is not empty after deleting all words")
+	}
+}
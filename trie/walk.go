@@ -0,0 +1,198 @@
+package trie
+
+import (
+	"unicode/utf8"
+)
+
+// walkNode 可按字典序遍历的前缀树节点
+type walkNode interface {
+	// isKey 该节点是否为某个单词的结尾
+	isKey() bool
+	// eachChild 按边上字符串的字典序遍历子节点，fn返回false时停止并返回false
+	eachChild(fn func(label string, child walkNode) bool) bool
+}
+
+// byteLabels 单个字节对应的边字符串，避免遍历时重复分配
+var byteLabels = func() (labels [256]string) {
+	for i := range labels {
+		labels[i] = string([]byte{byte(i)})
+	}
+	return labels
+}()
+
+// comparePath 比较path与after：path是after的前缀时返回0，否则按字典序返回-1或1
+func comparePath(path []byte, after string) int {
+	for i := 0; i < len(path); i++ {
+		if i == len(after) || path[i] > after[i] {
+			return 1
+		}
+		if path[i] < after[i] {
+			return -1
+		}
+	}
+	return 0
+}
+
+// walk 按字典序深度优先遍历以n为根的子树，path为从根到达n的字符串
+// bounded为true时只遍历字典序大于after的单词，fn返回false时停止遍历并返回false
+func walk(n walkNode, path []byte, after string, bounded bool, fn func(word string, n walkNode) bool) bool {
+	if bounded {
+		switch comparePath(path, after) {
+		case -1:
+			// 整棵子树都不大于after
+			return true
+		case 1:
+			bounded = false
+		}
+	}
+	// bounded时path是after的前缀，不大于after
+	if !bounded && n.isKey() && !fn(string(path), n) {
+		return false
+	}
+	return n.eachChild(func(label string, child walkNode) bool {
+		var l = len(path)
+		path = append(path, label...)
+		var ok = walk(child, path, after, bounded, fn)
+		path = path[:l]
+		return ok
+	})
+}
+
+// walkWords 遍历以n为根的子树中的单词，prefix为从根到达n的字符串
+func walkWords(n walkNode, prefix, after string, bounded bool, fn func(word string) bool) {
+	walk(n, []byte(prefix), after, bounded, func(word string, _ walkNode) bool {
+		return fn(word)
+	})
+}
+
+// keysWithPrefix 按字典序返回t中以prefix为前缀的至多limit个单词，limit<=0时返回全部
+func keysWithPrefix(t ExtendedTrie, prefix string, limit int) []string {
+	var keys []string
+	t.Walk(prefix, func(word string) bool {
+		keys = append(keys, word)
+		return limit <= 0 || len(keys) < limit
+	})
+	return keys
+}
+
+// Cursor 按字典序分页遍历以prefix为前缀的单词的游标
+// 保存Last()返回的位置，之后可用NewCursorAfter从该位置继续
+type Cursor struct {
+	t      ExtendedTrie
+	prefix string
+	// 上一页的最后一个单词
+	last    string
+	started bool
+	done    bool
+}
+
+// NewCursor 从头遍历t中以prefix为前缀的单词
+func NewCursor(t ExtendedTrie, prefix string) *Cursor {
+	return &Cursor{t: t, prefix: prefix}
+}
+
+// NewCursorAfter 从字典序大于after的第一个单词开始遍历t中以prefix为前缀的单词
+func NewCursorAfter(t ExtendedTrie, prefix, after string) *Cursor {
+	return &Cursor{t: t, prefix: prefix, last: after, started: true}
+}
+
+// Next 返回下一页的至多n个单词，n<=0时返回剩余的所有单词
+func (c *Cursor) Next(n int) []string {
+	if c.done {
+		return nil
+	}
+	var (
+		keys []string
+		fn   = func(word string) bool {
+			keys = append(keys, word)
+			return n <= 0 || len(keys) < n
+		}
+	)
+	if c.started {
+		c.t.WalkAfter(c.prefix, c.last, fn)
+	} else {
+		c.t.Walk(c.prefix, fn)
+	}
+	if len(keys) > 0 {
+		c.last, c.started = keys[len(keys)-1], true
+	}
+	if n <= 0 || len(keys) < n {
+		c.done = true
+	}
+	return keys
+}
+
+// Last 已返回的最后一个单词
+func (c *Cursor) Last() string {
+	return c.last
+}
+
+// Done 是否已遍历完
+func (c *Cursor) Done() bool {
+	return c.done
+}
+
+// walkable 可以取得前缀对应节点的前缀树
+type walkable interface {
+	// prefixNode 以prefix为前缀的单词所在子树的根节点及从根到达它的字符串，不存在时返回nil
+	prefixNode(prefix string) (walkNode, string)
+}
+
+// runeMatcher 在前缀树上逐字符推进的匹配状态，用于模式匹配、模糊搜索等按字符剪枝的遍历
+type runeMatcher interface {
+	// step 读入一个字符，返回false表示之后不可能再匹配，可以剪枝
+	step(r rune) bool
+	// depth 已读入的字符个数
+	depth() int
+	// rewind 回退到读入depth个字符时的状态
+	rewind(depth int)
+	// accept 当前状态是否匹配
+	accept() bool
+}
+
+// feed 将path末尾pending个尚未读入的字节中的完整字符读入m
+// 返回剩余的不完整字符的字节数，m无法再匹配时返回-1
+func feed(m runeMatcher, path []byte, pending int) int {
+	var rest = path[len(path)-pending:]
+	for len(rest) > 0 && utf8.FullRune(rest) {
+		var r, size = utf8.DecodeRune(rest)
+		if !m.step(r) {
+			return -1
+		}
+		rest = rest[size:]
+	}
+	return len(rest)
+}
+
+// matchWalk 按字典序遍历以n为根的子树中被m接受的单词，m不可能再匹配的子树会被剪除
+// 按字节组织的前缀树中一个字符可能跨越多层节点，pending为path末尾尚未组成完整字符的字节数
+func matchWalk(n walkNode, path []byte, pending int, m runeMatcher, fn func(word string, n walkNode) bool) bool {
+	if pending == 0 && n.isKey() && m.accept() && !fn(string(path), n) {
+		return false
+	}
+	return n.eachChild(func(label string, child walkNode) bool {
+		var (
+			l  = len(path)
+			d  = m.depth()
+			ok = true
+		)
+		path = append(path, label...)
+		if rest := feed(m, path, pending+len(label)); rest >= 0 {
+			ok = matchWalk(child, path, rest, m, fn)
+		}
+		m.rewind(d)
+		path = path[:l]
+		return ok
+	})
+}
+
+// matchTrie 按字典序遍历t中以prefix为前缀且被m接受的单词，t必须实现walkable
+func matchTrie(t walkable, prefix string, m runeMatcher, fn func(word string, n walkNode) bool) {
+	var n, path = t.prefixNode(prefix)
+	if n == nil {
+		return
+	}
+	if pending := feed(m, []byte(path), len(path)); pending >= 0 {
+		matchWalk(n, []byte(path), pending, m, fn)
+	}
+}
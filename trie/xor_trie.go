@@ -0,0 +1,178 @@
+package trie
+
+// XorTrie 按二进制位组织的整数前缀树（01字典树），用于与异或相关的查询
+// 从最高位开始，第i层按整数的第bits-1-i位分叉，可重复插入同一个数
+type XorTrie struct {
+	bits uint
+	root *xorNode
+}
+
+type xorNode struct {
+	children [2]*xorNode
+	// 子树中整数的个数（计重复）
+	count int
+}
+
+// NewXorTrie 创建存放bits位无符号整数的XorTrie，bits取值为1到64，超出范围时按64
+func NewXorTrie(bits uint) *XorTrie {
+	if bits < 1 || bits > 64 {
+		bits = 64
+	}
+	return &XorTrie{bits: bits, root: new(xorNode)}
+}
+
+// Bits 整数的位数
+func (t *XorTrie) Bits() uint {
+	return t.bits
+}
+
+// Len 整数的个数（计重复）
+func (t *XorTrie) Len() int {
+	return t.root.count
+}
+
+// bitAt x的第i位
+func bitAt(x uint64, i uint) int {
+	return int(x>>i) & 1
+}
+
+// mask 只保留低bits位
+func (t *XorTrie) mask(x uint64) uint64 {
+	if t.bits == 64 {
+		return x
+	}
+	return x & (1<<t.bits - 1)
+}
+
+// Insert 插入x，只使用x的低bits位
+func (t *XorTrie) Insert(x uint64) {
+	x = t.mask(x)
+	var curNode = t.root
+	curNode.count++
+	for i := int(t.bits) - 1; i >= 0; i-- {
+		var b = bitAt(x, uint(i))
+		if curNode.children[b] == nil {
+			curNode.children[b] = new(xorNode)
+		}
+		curNode = curNode.children[b]
+		curNode.count++
+	}
+}
+
+// Count x的个数
+func (t *XorTrie) Count(x uint64) int {
+	x = t.mask(x)
+	var curNode = t.root
+	for i := int(t.bits) - 1; i >= 0 && curNode != nil; i-- {
+		curNode = curNode.children[bitAt(x, uint(i))]
+	}
+	if curNode == nil {
+		return 0
+	}
+	return curNode.count
+}
+
+// Remove 删除一个x并剪除空的分支，x不存在时返回false
+func (t *XorTrie) Remove(x uint64) bool {
+	if t.Count(x) == 0 {
+		return false
+	}
+	x = t.mask(x)
+	var curNode = t.root
+	curNode.count--
+	for i := int(t.bits) - 1; i >= 0; i-- {
+		var (
+			b    = bitAt(x, uint(i))
+			next = curNode.children[b]
+		)
+		next.count--
+		if next.count == 0 {
+			curNode.children[b] = nil
+			return true
+		}
+		curNode = next
+	}
+	return true
+}
+
+// MaxXor 已存的整数中与x异或的最大值，为空时返回false
+// 从高位开始尽量走与x的当前位相反的分支
+func (t *XorTrie) MaxXor(x uint64) (uint64, bool) {
+	return t.extremeXor(x, 1)
+}
+
+// MinXor 已存的整数中与x异或的最小值，为空时返回false
+// 从高位开始尽量走与x的当前位相同的分支
+func (t *XorTrie) MinXor(x uint64) (uint64, bool) {
+	return t.extremeXor(x, 0)
+}
+
+// extremeXor prefer为1时求最大异或值，为0时求最小异或值
+func (t *XorTrie) extremeXor(x uint64, prefer int) (uint64, bool) {
+	if t.root.count == 0 {
+		return 0, false
+	}
+	x = t.mask(x)
+	var (
+		curNode = t.root
+		ans     uint64
+	)
+	for i := int(t.bits) - 1; i >= 0; i-- {
+		var b = bitAt(x, uint(i)) ^ prefer
+		if curNode.children[b] == nil {
+			b ^= 1
+		}
+		if b != bitAt(x, uint(i)) {
+			ans |= 1 << uint(i)
+		}
+		curNode = curNode.children[b]
+	}
+	return ans, true
+}
+
+// CountXorLess 已存的整数中与x异或后小于bound的个数（计重复）
+func (t *XorTrie) CountXorLess(x, bound uint64) int {
+	x = t.mask(x)
+	if t.bits < 64 && bound > t.mask(^uint64(0)) {
+		// 异或结果都小于bound
+		return t.root.count
+	}
+	var (
+		curNode = t.root
+		ans     int
+	)
+	for i := int(t.bits) - 1; i >= 0 && curNode != nil; i-- {
+		var xb, bb = bitAt(x, uint(i)), bitAt(bound, uint(i))
+		if bb == 1 {
+			// 该位异或为0的分支全部小于bound
+			if child := curNode.children[xb]; child != nil {
+				ans += child.count
+			}
+		}
+		// 沿异或结果与bound该位相同的分支继续
+		curNode = curNode.children[xb^bb]
+	}
+	return ans
+}
+
+// KthSmallest 第k小的整数（k从1开始，计重复），k超出范围时返回false
+func (t *XorTrie) KthSmallest(k int) (uint64, bool) {
+	if k < 1 || k > t.root.count {
+		return 0, false
+	}
+	var (
+		curNode = t.root
+		ans     uint64
+	)
+	for i := int(t.bits) - 1; i >= 0; i-- {
+		if zero := curNode.children[0]; zero != nil && zero.count >= k {
+			curNode = zero
+			continue
+		} else if zero != nil {
+			k -= zero.count
+		}
+		ans |= 1 << uint(i)
+		curNode = curNode.children[1]
+	}
+	return ans, true
+}
@@ -0,0 +1,98 @@
+package trie
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestXorTrie(t *testing.T) {
+	var tr = NewXorTrie(5)
+	if _, ok := tr.MaxXor(0); ok {
+		t.Fatalf("MaxXor() on empty trie = true")
+	}
+	for _, x := range []uint64{3, 10, 5, 25, 2, 8, 5} {
+		tr.Insert(x)
+	}
+	tests := []struct {
+		name string
+		got  uint64
+		want uint64
+	}{
+		{name: "MaxXor(5)", got: first(tr.MaxXor(5)), want: 28},
+		{name: "MinXor(5)", got: first(tr.MinXor(5)), want: 0},
+		{name: "MinXor(9)", got: first(tr.MinXor(9)), want: 1},
+		{name: "KthSmallest(1)", got: first(tr.KthSmallest(1)), want: 2},
+		{name: "KthSmallest(4)", got: first(tr.KthSmallest(4)), want: 5},
+		{name: "KthSmallest(7)", got: first(tr.KthSmallest(7)), want: 25},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+	if tr.Count(5) != 2 || !tr.Remove(5) || tr.Count(5) != 1 || tr.Remove(7) || tr.Len() != 6 {
+		t.Fatalf("Count/Remove mismatch")
+	}
+	if _, ok := tr.KthSmallest(7); ok {
+		t.Fatalf("KthSmallest(7) = true after Remove")
+	}
+	// 5位整数的异或结果都小于32
+	if got := tr.CountXorLess(1, 1000); got != 6 {
+		t.Fatalf("CountXorLess(1, 1000) = %v, want 6", got)
+	}
+}
+
+func first(x uint64, _ bool) uint64 {
+	return x
+}
+
+func TestXorTrie_Random(t *testing.T) {
+	var (
+		r      = rand.New(rand.NewSource(1))
+		tr     = NewXorTrie(64)
+		values []uint64
+	)
+	for i := 0; i < 2000; i++ {
+		var x = r.Uint64() >> uint(r.Intn(64))
+		if len(values) > 0 && r.Intn(3) == 0 {
+			var j = r.Intn(len(values))
+			if !tr.Remove(values[j]) {
+				t.Fatalf("Remove(%v) = false", values[j])
+			}
+			values = append(values[:j], values[j+1:]...)
+		} else {
+			tr.Insert(x)
+			values = append(values, x)
+		}
+		if len(values) == 0 {
+			continue
+		}
+		var (
+			q, bound   = r.Uint64(), r.Uint64() >> uint(r.Intn(64))
+			maxX, minX = uint64(0), ^uint64(0)
+			less       int
+			sorted     = append([]uint64(nil), values...)
+			k          = 1 + r.Intn(len(values))
+			gotMax, _  = tr.MaxXor(q)
+			gotMin, _  = tr.MinXor(q)
+			gotKth, _  = tr.KthSmallest(k)
+			gotLess    = tr.CountXorLess(q, bound)
+		)
+		for _, v := range values {
+			if v^q > maxX {
+				maxX = v ^ q
+			}
+			if v^q < minX {
+				minX = v ^ q
+			}
+			if v^q < bound {
+				less++
+			}
+		}
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		if gotMax != maxX || gotMin != minX || gotKth != sorted[k-1] || gotLess != less {
+			t.Fatalf("got %v %v %v %v, want %v %v %v %v", gotMax, gotMin, gotKth, gotLess, maxX, minX, sorted[k-1], less)
+		}
+	}
+}